@@ -0,0 +1,106 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	backupInfix      = ".ssh-aliases-"
+	backupSuffix     = ".bak"
+	backupTimeLayout = "20060102-150405.000"
+)
+
+// backups manages timestamped copies of a file, placed next to it
+// and named `<file name>.ssh-aliases-<timestamp>.bak`
+type backups struct {
+	file string
+	now  func() time.Time
+}
+
+type backup struct {
+	path    string
+	created time.Time
+}
+
+func newBackups(file string) *backups {
+	return &backups{
+		file: file,
+		now:  time.Now,
+	}
+}
+
+// create copies the current content of the file, if it exists and differs from the latest backup,
+// and removes the oldest backups above the limit, except for the kept ones
+func (b *backups) create(limit int, kept ...string) error {
+	content, err := os.ReadFile(b.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := b.list()
+	if err != nil {
+		return err
+	}
+	// unchanged file does not need another copy
+	if len(existing) > 0 {
+		latest, err := os.ReadFile(existing[0].path)
+		if err == nil && bytes.Equal(latest, content) {
+			return nil
+		}
+	}
+	path := b.file + backupInfix + b.now().Format(backupTimeLayout) + backupSuffix
+	if err = writeFileAtomically(path, content); err != nil {
+		return fmt.Errorf("could not create backup of `%s`: %s", b.file, err.Error())
+	}
+	existing, err = b.list()
+	if err != nil {
+		return err
+	}
+	for i := limit; i < len(existing); i++ {
+		if slices.Contains(kept, existing[i].path) {
+			continue
+		}
+		if err = os.Remove(existing[i].path); err != nil {
+			return fmt.Errorf("could not remove old backup `%s`: %s", existing[i].path, err.Error())
+		}
+	}
+	return nil
+}
+
+// list returns existing backups, the newest first
+func (b *backups) list() ([]backup, error) {
+	prefix := filepath.Base(b.file) + backupInfix
+	entries, err := os.ReadDir(filepath.Dir(b.file))
+	if err != nil {
+		return nil, err
+	}
+	var found []backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+		created, err := time.ParseInLocation(backupTimeLayout,
+			strings.TrimSuffix(strings.TrimPrefix(name, prefix), backupSuffix), time.Local)
+		if err != nil {
+			continue
+		}
+		found = append(found, backup{
+			path:    filepath.Join(filepath.Dir(b.file), name),
+			created: created,
+		})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].created.After(found[j].created)
+	})
+	return found, nil
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedClock returns consecutive seconds starting at 2020-01-02 10:00:00
+func fixedClock() func() time.Time {
+	current := time.Date(2020, 1, 2, 10, 0, 0, 0, time.Local)
+	return func() time.Time {
+		current = current.Add(time.Second)
+		return current
+	}
+}
+
+func TestShouldKeepLimitedNumberOfBackups(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "config")
+	b := newBackups(file)
+	b.now = fixedClock()
+
+	// when
+	for _, content := range []string{"1", "2", "2", "3", "4"} {
+		assert.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+		assert.NoError(t, b.create(2))
+	}
+
+	// then
+	existing, err := b.list()
+	assert.NoError(t, err)
+	assert.Equal(t, []backup{
+		{filepath.Join(filepath.Dir(file), "config.ssh-aliases-20200102-100004.000.bak"),
+			time.Date(2020, 1, 2, 10, 0, 4, 0, time.Local)},
+		{filepath.Join(filepath.Dir(file), "config.ssh-aliases-20200102-100003.000.bak"),
+			time.Date(2020, 1, 2, 10, 0, 3, 0, time.Local)},
+	}, existing)
+	content, _ := os.ReadFile(existing[1].path)
+	assert.Equal(t, "3", string(content))
+}
+
+func TestShouldNotBackupNotExistingFile(t *testing.T) {
+	t.Parallel()
+
+	// given
+	b := newBackups(filepath.Join(t.TempDir(), "config"))
+
+	// when
+	err := b.create(2)
+
+	// then
+	assert.NoError(t, err)
+	existing, _ := b.list()
+	assert.Empty(t, existing)
+}
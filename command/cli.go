@@ -2,12 +2,19 @@
 package command
 
 import (
+	"fmt"
+	"os"
+	"os/signal"
 	"os/user"
+	"syscall"
+	"time"
 
 	"path/filepath"
 
 	"io"
 
+	"github.com/dankraw/ssh-aliases/config"
+	"github.com/dankraw/ssh-aliases/keywords"
 	"github.com/urfave/cli"
 )
 
@@ -37,29 +44,125 @@ func configureCLI(version string, writer io.Writer) (*cli.App, error) {
 	if err != nil {
 		return nil, err
 	}
-	var scanDir string
+	var scanDirs cli.StringSlice
+	var verbose bool
+	var failFast bool
+	var recursive bool
+	var include cli.StringSlice
+	var exclude cli.StringSlice
+	var keepPropertyOrder bool
+	var keywordStrictness string
+	var allowedKeywords cli.StringSlice
 	var save bool
 	var force bool
 	var file string
 	var hostsFile string
+	var jsonOutput bool
+	var watch bool
+	var debounce time.Duration
+	var managedBlock bool
+	var blockPositionValue string
+	var backupsLimit int
+	var showDiff bool
+	var dryRun bool
+	var formatValue string
+	var indentWidth int
+	var indentTabs bool
+	var sourceComments bool
 
 	app := cli.NewApp()
 	app.Version = version
 	app.Name = "ssh-aliases"
 	app.Usage = "template driven ssh config generation"
 	app.Flags = []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "scan, s",
+			Usage: "input files dir, defaults to ~/" + sshAliasesDir + " (may be repeated, " +
+				"host and config definitions from later dirs override the ones from earlier dirs)",
+			Value: &scanDirs,
+		},
+		cli.BoolFlag{
+			Name:        "verbose",
+			Usage:       "print additional information about processed definitions to stderr",
+			Destination: &verbose,
+		},
+		cli.BoolFlag{
+			Name:        "fail-fast",
+			Usage:       "stop at the first error, instead of reporting all errors found in input files",
+			Destination: &failFast,
+		},
+		cli.BoolFlag{
+			Name:        "recursive, r",
+			Usage:       "scan child directories of input files dir",
+			Destination: &recursive,
+		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "glob pattern of input files to read, relative to input files dir (may be repeated)",
+			Value: &include,
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "glob pattern of input files or dirs to skip, relative to input files dir (may be repeated)",
+			Value: &exclude,
+		},
+		cli.BoolFlag{
+			Name:        "keep-property-order",
+			Usage:       "keep config properties in order of their declaration, instead of sorting them alphabetically",
+			Destination: &keepPropertyOrder,
+		},
 		cli.StringFlag{
-			Name:        "scan, s",
-			Usage:       "input files dir",
-			Value:       filepath.Join(homeDir, sshAliasesDir),
-			Destination: &scanDir,
+			Name:        "keywords",
+			Usage:       "check keywords of config properties against the catalog of ssh config keywords: off, warn or error",
+			Value:       keywords.Warn.String(),
+			Destination: &keywordStrictness,
+		},
+		cli.StringSliceFlag{
+			Name:  "allow-keyword",
+			Usage: "custom keyword accepted regardless of keywords check, like a keyword of patched ssh build (may be repeated)",
+			Value: &allowedKeywords,
 		},
 	}
+	configReader := func() (*config.Reader, error) {
+		strictness, err := keywords.ParseStrictness(keywordStrictness)
+		if err != nil {
+			return nil, err
+		}
+		options := config.ReaderOptions{
+			Scan: config.ScanOptions{
+				Recursive: recursive,
+				Include:   include,
+				Exclude:   exclude,
+			},
+			FailFast:          failFast,
+			KeepPropertyOrder: keepPropertyOrder,
+			Keywords: config.KeywordOptions{
+				Strictness: strictness,
+				Allowed:    allowedKeywords,
+			},
+		}
+		if verbose {
+			options.Verbose = os.Stderr
+		}
+		return config.NewReaderWithOptions(options), nil
+	}
+	scanned := func() []string {
+		if len(scanDirs) == 0 {
+			return []string{filepath.Join(homeDir, sshAliasesDir)}
+		}
+		return scanDirs
+	}
 	app.Commands = []cli.Command{{
 		Name:    "list",
 		Aliases: []string{"l"},
 		Usage:   "Prints the list of host definitions",
 		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:        "format",
+				Usage:       "output format: text, json or yaml",
+				Value:       string(formatText),
+				Destination: &formatValue,
+			},
 			cli.StringFlag{
 				Name:        "hosts-file",
 				Usage:       "input hosts file for regexp compilation",
@@ -67,13 +170,23 @@ func configureCLI(version string, writer io.Writer) (*cli.App, error) {
 			},
 		},
 		Action: func(_ *cli.Context) error {
+			format, err := parseOutputFormat(formatValue)
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), 1)
+			}
 			hosts, err := readHostsFile(hostsFile)
 			if err != nil {
-				return cli.NewExitError(err.Error(), 1)
+				return cli.NewExitError(errorMessage(err), 1)
+			}
+			reader, err := configReader()
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), 1)
 			}
-			err = newListCommand(writer).execute(scanDir, hosts)
+			list := newListCommand(writer, reader)
+			list.format = format
+			err = list.execute(scanned(), hosts)
 			if err != nil {
-				return cli.NewExitError(err.Error(), 1)
+				return cli.NewExitError(errorMessage(err), 1)
 			}
 			return nil
 		},
@@ -82,6 +195,12 @@ func configureCLI(version string, writer io.Writer) (*cli.App, error) {
 		Aliases: []string{"c"},
 		Usage:   "Prints compiled ssh config file or writes it to a file",
 		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:        "format",
+				Usage:       "output format: text, json or yaml, applies only to output printed to stdout",
+				Value:       string(formatText),
+				Destination: &formatValue,
+			},
 			cli.BoolFlag{
 				Name:        "save",
 				Usage:       "write compilation output to file instead of printing to stdout",
@@ -103,20 +222,203 @@ func configureCLI(version string, writer io.Writer) (*cli.App, error) {
 				Usage:       "input hosts file for regexp compilation",
 				Destination: &hostsFile,
 			},
+			cli.BoolFlag{
+				Name: "managed-block",
+				Usage: "write compilation output between '" + managedBlockBegin + "' and '" + managedBlockEnd +
+					"' markers of the file, keeping the rest of its content",
+				Destination: &managedBlock,
+			},
+			cli.StringFlag{
+				Name:        "block-position",
+				Usage:       "where managed block is inserted if the file does not contain it yet: top or bottom",
+				Value:       string(blockTop),
+				Destination: &blockPositionValue,
+			},
+			cli.IntFlag{
+				Name:        "backups",
+				Usage:       "number of timestamped backups of the file kept when it is overwritten, 0 disables backups",
+				Value:       defaultBackupsLimit,
+				Destination: &backupsLimit,
+			},
+			cli.IntFlag{
+				Name:        "indent",
+				Usage:       "number of spaces (or tabs, with --indent-tabs) that host properties are indented with",
+				Value:       defaultIndentWidth,
+				Destination: &indentWidth,
+			},
+			cli.BoolFlag{
+				Name:        "indent-tabs",
+				Usage:       "indent host properties with tabs instead of spaces",
+				Destination: &indentTabs,
+			},
+			cli.BoolFlag{
+				Name:        "source-comments",
+				Usage:       "precede each Host with '# from <file>:<host definition>' comment",
+				Destination: &sourceComments,
+			},
+			cli.BoolFlag{
+				Name:        "diff",
+				Usage:       "print unified diff of the file and compilation output (written only with --save)",
+				Destination: &showDiff,
+			},
+			cli.BoolFlag{
+				Name: "dry-run",
+				Usage: fmt.Sprintf("only report if the file differs from compilation output, "+
+					"exits with %d when it does", driftExitCode),
+				Destination: &dryRun,
+			},
+			cli.BoolFlag{
+				Name: "watch, w",
+				Usage: "keep running and write compilation output to file whenever input files or hosts file change, " +
+					"the file is left untouched when compilation fails",
+				Destination: &watch,
+			},
+			cli.DurationFlag{
+				Name:        "debounce",
+				Usage:       "time without further changes that triggers compilation in watch mode",
+				Value:       defaultWatchDebounce,
+				Destination: &debounce,
+			},
 		},
 		Action: func(_ *cli.Context) error {
-			var err error
+			position, err := parseBlockPosition(blockPositionValue)
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), 1)
+			}
+			format, err := parseOutputFormat(formatValue)
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), 1)
+			}
+			if format != formatText && (save || watch || showDiff || dryRun) {
+				return cli.NewExitError("`--format` option can not be used when ssh config file is written or compared", 1)
+			}
+			if indentWidth < 0 {
+				return cli.NewExitError("`--indent` option can not be negative", 1)
+			}
+			render := renderOptions{
+				indentWidth:    indentWidth,
+				tabs:           indentTabs,
+				sourceComments: sourceComments,
+			}
+			options := saveOptions{
+				managedBlock:  managedBlock,
+				blockPosition: position,
+				diff:          showDiff,
+				render:        render,
+				backups:       backupsLimit,
+			}
+			reader, err := configReader()
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), 1)
+			}
+			if watch {
+				err = watchCompilation(file, options, force, debounce, writer, reader, scanned(), hostsFile)
+				if err != nil {
+					return cli.NewExitError(errorMessage(err), 1)
+				}
+				return nil
+			}
 			hosts, err := readHostsFile(hostsFile)
 			if err != nil {
-				return cli.NewExitError(err.Error(), 1)
+				return cli.NewExitError(errorMessage(err), 1)
+			}
+			if dryRun || (showDiff && !save) {
+				drift, err := newDiffCommand(file, options, writer, reader).
+					execute(scanned(), hosts, showDiff, dryRun)
+				if err != nil {
+					return cli.NewExitError(errorMessage(err), 1)
+				}
+				if dryRun && drift {
+					return cli.NewExitError("", driftExitCode)
+				}
+				return nil
 			}
 			if save {
-				err = newCompileSaveCommand(file).execute(scanDir, force, hosts)
+				err = newCompileSaveCommand(file, options, reader).execute(scanned(), force, hosts)
 			} else {
-				err = newCompileCommand(writer).execute(scanDir, hosts)
+				compile := newCompileCommand(writer, reader)
+				compile.format = format
+				compile.render = render
+				err = compile.execute(scanned(), hosts)
+			}
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), 1)
+			}
+			return nil
+		},
+	}, {
+		Name:    "validate",
+		Aliases: []string{"v"},
+		Usage: "Checks if input files compile without errors, without writing ssh config " +
+			"(exits with 1 when errors are found, 2 when validation could not be performed)",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:        "json",
+				Usage:       "print diagnostics in JSON format",
+				Destination: &jsonOutput,
+			},
+			cli.StringFlag{
+				Name:        "hosts-file",
+				Usage:       "input hosts file for regexp compilation",
+				Destination: &hostsFile,
+			},
+		},
+		Action: func(_ *cli.Context) error {
+			hosts, err := readHostsFile(hostsFile)
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), validationNotPerformedExitCode)
+			}
+			reader, err := configReader()
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), validationNotPerformedExitCode)
+			}
+			valid, err := newValidateCommand(writer, jsonOutput, reader).execute(scanned(), hosts)
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), validationNotPerformedExitCode)
+			}
+			if !valid {
+				return cli.NewExitError("", validationFailedExitCode)
 			}
+			return nil
+		},
+	}, {
+		Name:      "restore",
+		Usage:     "Lists backups of ssh config file written by compile command or restores one of them",
+		ArgsUsage: "[number on the list or file name of the backup to restore, lists backups if omitted]",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:        "file",
+				Usage:       "restored file path",
+				Destination: &file,
+				Value:       filepath.Join(homeDir, ".ssh", "config"),
+			},
+			cli.IntFlag{
+				Name:        "backups",
+				Usage:       "number of timestamped backups of the file kept, restored file is backed up as well",
+				Value:       defaultBackupsLimit,
+				Destination: &backupsLimit,
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			err := newRestoreCommand(file, writer).execute(ctx.Args().First(), backupsLimit)
 			if err != nil {
-				return cli.NewExitError(err.Error(), 1)
+				return cli.NewExitError(errorMessage(err), 1)
+			}
+			return nil
+		},
+	}, {
+		Name:      "import",
+		Aliases:   []string{"i"},
+		Usage:     "Converts ssh config file into ssh-aliases HCL definitions and prints them",
+		ArgsUsage: "[ssh config file, defaults to ~/.ssh/config]",
+		Action: func(ctx *cli.Context) error {
+			file := ctx.Args().First()
+			if file == "" {
+				file = filepath.Join(homeDir, ".ssh", "config")
+			}
+			err := newImportCommand(writer, homeDir).execute(file)
+			if err != nil {
+				return cli.NewExitError(errorMessage(err), 1)
 			}
 			return nil
 		},
@@ -124,6 +426,30 @@ func configureCLI(version string, writer io.Writer) (*cli.App, error) {
 	return app, nil
 }
 
+// watchCompilation confirms overwriting of the output file once and then watches for changes until interrupted
+func watchCompilation(file string, options saveOptions, force bool, debounce time.Duration, writer io.Writer,
+	configReader *config.Reader, dirs []string, hostsFile string) error {
+	if !force {
+		confirmed, err := newConfirm(os.Stdin).requireConfirmationIfFileExists(file)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Printf("Exiting without writing changes to %s", file)
+			return nil
+		}
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	stop := make(chan struct{})
+	go func() {
+		<-signals
+		close(stop)
+	}()
+	return newWatchCommand(file, options, debounce, writer, configReader).execute(dirs, hostsFile, stop)
+}
+
 func homeDir() (string, error) {
 	usr, err := user.Current()
 	if err != nil {
@@ -5,26 +5,55 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/dankraw/ssh-aliases/compiler"
 	"github.com/dankraw/ssh-aliases/config"
 )
 
 type compileSaveCommand struct {
-	file    string
-	confirm *confirm
+	file         string
+	options      saveOptions
+	confirm      *confirm
+	configReader *config.Reader
 }
 
-func newCompileSaveCommand(file string) *compileSaveCommand {
+func newCompileSaveCommand(file string, options saveOptions, configReader *config.Reader) *compileSaveCommand {
 	return &compileSaveCommand{
-		file:    file,
-		confirm: newConfirm(os.Stdin),
+		file:         file,
+		options:      options,
+		confirm:      newConfirm(os.Stdin),
+		configReader: configReader,
 	}
 }
 
-func (c *compileSaveCommand) execute(dir string, force bool, hosts []string) error {
+func (c *compileSaveCommand) execute(dirs []string, force bool, hosts []string) error {
+	buffer := new(bytes.Buffer)
+	compile := newCompileCommand(buffer, c.configReader)
+	compile.render = c.options.render
+	err := compile.execute(dirs, hosts)
+	if err != nil {
+		return err
+	}
+	content, err := c.options.outputContent(c.file, buffer.Bytes())
+	if err != nil {
+		return err
+	}
+	diff, err := unifiedDiff(c.file, content)
+	if err != nil {
+		return err
+	}
+	exists, err := c.confirm.fileExists(c.file)
+	if err != nil {
+		return err
+	}
+	if exists && diff == "" {
+		fmt.Printf("No changes to write to %s\n", c.file)
+		return nil
+	}
 	if !force {
-		confirmed, err := c.confirm.requireConfirmationIfFileExists(c.file)
+		confirmed, err := c.confirm.requireConfirmationOfChanges(c.file, diff)
 		if err != nil {
 			return err
 		}
@@ -34,70 +63,125 @@ func (c *compileSaveCommand) execute(dir string, force bool, hosts []string) err
 			fmt.Printf("Exiting without writing changes to %s", c.file)
 			return nil
 		}
+	} else if c.options.diff {
+		fmt.Print(diff)
 	}
-	buffer := new(bytes.Buffer)
-	err := newCompileCommand(buffer).execute(dir, hosts)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(c.file, buffer.Bytes(), 0o600)
+	return c.options.write(c.file, content)
 }
 
 type compileCommand struct {
-	indentation  int
-	writer       io.Writer
-	configReader *config.Reader
-	compiler     *compiler.Compiler
-	validator    *compiler.Validator
+	render            renderOptions
+	format            outputFormat
+	writer            io.Writer
+	diagnosticsWriter io.Writer
+	configReader      *config.Reader
+	compiler          *compiler.Compiler
+	validator         *compiler.Validator
 }
 
-func newCompileCommand(writer io.Writer) *compileCommand {
+func newCompileCommand(writer io.Writer, configReader *config.Reader) *compileCommand {
 	return &compileCommand{
-		indentation:  4,
-		writer:       writer,
-		configReader: config.NewReader(),
-		compiler:     compiler.NewCompiler(),
-		validator:    compiler.NewValidator(),
+		render:            defaultRenderOptions(),
+		format:            formatText,
+		writer:            writer,
+		diagnosticsWriter: os.Stderr,
+		configReader:      configReader,
+		compiler:          compiler.NewCompiler(),
+		validator:         compiler.NewValidator(),
 	}
 }
 
-func (c *compileCommand) execute(dir string, hosts []string) error {
-	ctx, err := c.configReader.ReadConfigs(dir)
+func (c *compileCommand) execute(dirs []string, hosts []string) error {
+	result, diagnostics := compileAll(c.configReader, c.compiler, c.validator, dirs, hosts)
+	if err := diagnostics.Err(); err != nil {
+		return err
+	}
+	err := writeWarnings(c.diagnosticsWriter, diagnostics.Diagnostics())
 	if err != nil {
 		return err
 	}
-	var allResults []compiler.HostEntity
-	for _, s := range ctx.Sources {
-		for _, h := range s.Hosts {
-			results, err := c.compileHost(h, hosts)
-			if err != nil {
+	if c.format != formatText {
+		return writeHosts(c.writer, c.format, result)
+	}
+	// ssh uses the first obtained value of each option, so top matches take precedence over hosts
+	for _, m := range result.matches {
+		if m.Top {
+			if err = c.printMatchConfig(m); err != nil {
 				return err
 			}
-			allResults = append(allResults, results...)
 		}
 	}
-	err = c.validator.ValidateResults(allResults)
-	if err != nil {
-		return err
-	}
-	for _, result := range allResults {
-		err = c.printHostConfig(result)
-		if err != nil {
+	for _, h := range result.hosts {
+		if err = c.printHostConfig(h); err != nil {
 			return err
 		}
 	}
+	for _, m := range result.matches {
+		if !m.Top {
+			if err = c.printMatchConfig(m); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
-func (c *compileCommand) compileHost(host compiler.ExpandingHostConfig, hosts []string) ([]compiler.HostEntity, error) {
-	if host.IsRegexpHostDefinition() {
-		result, err := c.compiler.CompileRegexp(host, hosts)
-		return result, err
+// compilation contains all compiled hosts and matches, in order of their definitions
+type compilation struct {
+	hosts   []compiledHost
+	matches []compiledMatch
+}
+
+// compileAll reads and compiles all host definitions found in dirs, then validates the results,
+// problems found on the way are gathered by the returned collector
+func compileAll(configReader *config.Reader, c *compiler.Compiler, validator *compiler.Validator,
+	dirs []string, hosts []string) (compilation, *compiler.Collector) {
+	ctx, diagnostics := configReader.CollectConfigs(dirs...)
+	if diagnostics.Stopped() {
+		return compilation{}, diagnostics
+	}
+	var result compilation
+	var entities []compiler.HostEntity
+	for _, s := range ctx.Sources {
+		for _, h := range s.Hosts {
+			results, err := compileHost(c, h, hosts, diagnostics)
+			if err != nil {
+				if diagnostics.Add(err) {
+					return compilation{}, diagnostics
+				}
+				continue
+			}
+			for _, r := range results {
+				result.hosts = append(result.hosts, compiledHost{r, s.SourceName, h.AliasName, h.Priority})
+			}
+			entities = append(entities, results...)
+		}
+		for _, m := range s.Matches {
+			result.matches = append(result.matches, compiledMatch{m, s.SourceName})
+		}
+	}
+	if err := validator.ValidateResults(entities); err != nil {
+		diagnostics.Add(err)
 	}
-	return c.compiler.Compile(host)
+	sortHosts(result.hosts)
+	return result, diagnostics
 }
 
-func (c *compileCommand) printHostConfig(cfg compiler.HostEntity) error {
+// sortHosts orders hosts by their priority, hosts of the same priority keep the order of definitions,
+// so nothing is reordered unless a priority is set
+func sortHosts(hosts []compiledHost) {
+	sort.SliceStable(hosts, func(i, j int) bool {
+		return hosts[i].priority > hosts[j].priority
+	})
+}
+
+func (c *compileCommand) printHostConfig(cfg compiledHost) error {
+	if c.render.sourceComments {
+		_, err := fmt.Fprintf(c.writer, "# from %s:%s\n", cfg.source, cfg.definition)
+		if err != nil {
+			return err
+		}
+	}
 	_, err := fmt.Fprintf(c.writer, "Host %v\n", cfg.Host)
 	if err != nil {
 		return err
@@ -118,7 +202,41 @@ func (c *compileCommand) printHostConfig(cfg compiler.HostEntity) error {
 	return err
 }
 
-func (c *compileCommand) printHostConfigProperty(keyword string, value interface{}) error {
-	_, err := fmt.Fprintf(c.writer, "     %s %v\n", keyword, value)
+func (c *compileCommand) printMatchConfig(cfg compiledMatch) error {
+	if c.render.sourceComments {
+		_, err := fmt.Fprintf(c.writer, "# from %s:%s\n", cfg.source, cfg.Name)
+		if err != nil {
+			return err
+		}
+	}
+	criteria := make([]string, 0, len(cfg.Criteria))
+	for _, criterion := range cfg.Criteria {
+		if criterion.Value == "" {
+			criteria = append(criteria, criterion.Keyword)
+			continue
+		}
+		criteria = append(criteria, criterion.Keyword+" "+quotedArgument(criterion.Value))
+	}
+	_, err := fmt.Fprintf(c.writer, "Match %s\n", strings.Join(criteria, " "))
+	if err != nil {
+		return err
+	}
+	for _, e := range cfg.Config {
+		err = c.printHostConfigProperty(e.Key, e.Value)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(c.writer)
 	return err
 }
+
+func (c *compileCommand) printHostConfigProperty(keyword string, value interface{}) error {
+	for _, v := range renderedValues(keyword, value) {
+		_, err := fmt.Fprintf(c.writer, "%s%s %s\n", c.render.indentation(), keyword, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
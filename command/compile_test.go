@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/dankraw/ssh-aliases/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,10 +18,160 @@ func TestCompileCommandExecute(t *testing.T) {
 	hosts := []string{}
 
 	// when
-	err := newCompileCommand(buffer).execute(fixtureDir, hosts)
+	err := newCompileCommand(buffer, config.NewReader()).execute([]string{fixtureDir}, hosts)
 
 	// then
 	assert.NoError(t, err)
 	output, _ := os.ReadFile(filepath.Join(fixtureDir, "compile_result"))
 	assert.Equal(t, string(output), buffer.String())
 }
+
+func TestCompileCommandExecuteInJSONFormat(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+	compile := newCompileCommand(buffer, config.NewReader())
+	compile.format = formatJSON
+
+	// when
+	err := compile.execute([]string{fixtureDir}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	output, _ := os.ReadFile(filepath.Join(fixtureDir, "output", "compile_result.json"))
+	assert.Equal(t, string(output), buffer.String())
+}
+
+func TestCompileCommandExecuteWithMatchDefinitions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+
+	// when
+	err := newCompileCommand(buffer, config.NewReader()).execute([]string{filepath.Join(fixtureDir, "matches")}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Match canonical all\n"+
+		"     ForwardAgent no\n"+
+		"\n"+
+		"Host app\n"+
+		"     HostName app.example.com\n"+
+		"\n"+
+		"Match host *.prod.example.com,prod-* user deploy\n"+
+		"     IdentityFile \"~/.ssh/deploy key.pem\"\n\n", buffer.String())
+}
+
+func TestCompileCommandExecuteWithMultiValuedProperties(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+
+	// when
+	err := newCompileCommand(buffer, config.NewReader()).execute([]string{filepath.Join(fixtureDir, "multi_valued")}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Host tunnel\n"+
+		"     HostName tunnel.example.com\n"+
+		"     DynamicForward 1080\n"+
+		"     IdentityFile ~/.ssh/tunnel.pem\n"+
+		"     IdentityFile ~/.ssh/fallback.pem\n"+
+		"     LocalForward 8080 localhost:80\n"+
+		"     LocalForward 127.0.0.1:5432 db.internal:5432\n"+
+		"     RemoteForward 9000 localhost:9000\n"+
+		"     SendEnv LANG\n"+
+		"     SendEnv LC_*\n"+
+		"\n", buffer.String())
+}
+
+func TestCompileCommandShouldKeepOrderOfImportedSections(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dir := t.TempDir()
+	imported := new(bytes.Buffer)
+	err := newImportCommand(imported, dir).execute(filepath.Join(fixtureDir, "imported", "ssh_config"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "imported.hcl"), imported.Bytes(), 0o600))
+	buffer := new(bytes.Buffer)
+
+	// when
+	err = newCompileCommand(buffer, config.NewReader()).execute([]string{dir}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Host *\n"+
+		"     User ubuntu\n"+
+		"\n"+
+		"Host web\n"+
+		"     HostName web.example.com\n"+
+		"\n"+
+		"Host *.internal\n"+
+		"     ProxyJump jump\n"+
+		"\n"+
+		"Host jump\n"+
+		"     HostName jump.example.com\n"+
+		"\n", buffer.String())
+}
+
+func TestCompileCommandShouldCompileImportedValuesAsDeclared(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dir := t.TempDir()
+	imported := new(bytes.Buffer)
+	err := newImportCommand(imported, dir).execute(filepath.Join(fixtureDir, "imported_values", "ssh_config"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "imported.hcl"), imported.Bytes(), 0o600))
+	buffer := new(bytes.Buffer)
+
+	// when
+	err = newCompileCommand(buffer, config.NewReader()).execute([]string{dir}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Contains(t, imported.String(), `identity_file = ["~/my keys/a.pem", "~/.ssh/id_ed25519"]`)
+	assert.Equal(t, "Host files\n"+
+		"     HostName files.example.com\n"+
+		"     CanonicalDomains example.com example.org\n"+
+		"     IPQoS af21 cs1\n"+
+		"     IdentityFile \"~/my keys/a.pem\"\n"+
+		"     IdentityFile ~/.ssh/id_ed25519\n"+
+		"     LocalForward 8080 localhost:80\n"+
+		"     ProxyCommand ssh -W %h:%p \"gateway\"\n"+
+		"     SendEnv LANG\n"+
+		"     SendEnv LC_*\n"+
+		"     SetEnv \"GREETING=hello world\"\n"+
+		"     SetEnv EDITOR=vim\n"+
+		"     UserKnownHostsFile \"~/my hosts\" ~/.ssh/known_hosts\n"+
+		"\n", buffer.String())
+}
+
+func TestCompileCommandShouldOrderHostsByPriority(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+
+	// when
+	err := newCompileCommand(buffer, config.NewReader()).execute([]string{filepath.Join(fixtureDir, "host_order")}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "Host *.internal\n"+
+		"     ProxyJump jump\n"+
+		"\n"+
+		"Host jump\n"+
+		"     HostName jump.example.com\n"+
+		"\n"+
+		"Host app\n"+
+		"     HostName app.example.com\n"+
+		"\n"+
+		"Host *\n"+
+		"     User ubuntu\n"+
+		"\n", buffer.String())
+}
@@ -11,11 +11,13 @@ import (
 
 type confirm struct {
 	reader io.Reader
+	writer io.Writer
 }
 
 func newConfirm(reader io.Reader) *confirm {
 	return &confirm{
 		reader: reader,
+		writer: os.Stdout,
 	}
 }
 
@@ -29,9 +31,27 @@ func (c *confirm) requireConfirmationIfFileExists(path string) (bool, error) {
 	}
 	return c.confirmation(path)
 }
+
+// requireConfirmationOfChanges prints the diff of an existing file before asking for confirmation of overwriting it
+func (c *confirm) requireConfirmationOfChanges(path string, diff string) (bool, error) {
+	exists, err := c.fileExists(path)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return true, nil
+	}
+	if _, err = fmt.Fprint(c.writer, diff); err != nil {
+		return false, err
+	}
+	return c.confirmation(path)
+}
+
 func (c *confirm) confirmation(path string) (bool, error) {
 	r := bufio.NewReader(c.reader)
-	fmt.Printf("File `%s` already exists. Overwrite? (Y/n)\n", path)
+	if _, err := fmt.Fprintf(c.writer, "File `%s` already exists. Overwrite? (Y/n)\n", path); err != nil {
+		return false, err
+	}
 	response, err := r.ReadString('\n')
 	if err != nil {
 		return false, err
@@ -93,3 +93,26 @@ func TestGiveUp(t *testing.T) {
 	assert.False(t, confirmed)
 	assert.NoError(t, err)
 }
+
+func TestConfirmChanges(t *testing.T) {
+	t.Parallel()
+
+	// when
+	reader := NewTestReader("Y\n")
+	confirmed, err := newConfirm(reader).requireConfirmationOfChanges(filepath.Join(fixtureDir, "list_result"), "")
+
+	// then
+	assert.True(t, confirmed)
+	assert.NoError(t, err)
+}
+
+func TestConfirmChangesOfNotExistingFile(t *testing.T) {
+	t.Parallel()
+
+	// when
+	confirmed, err := newConfirm(NewTestReader("")).requireConfirmationOfChanges(filepath.Join(fixtureDir, "not_exists"), "")
+
+	// then
+	assert.True(t, confirmed)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/config"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// driftExitCode is returned by dry runs when the file differs from compilation output
+const driftExitCode = 2
+
+// unifiedDiff returns the unified diff of the current content of the file and its updated content,
+// or an empty string when there are no changes. Not existing file is compared as an empty one.
+func unifiedDiff(file string, updated []byte) (string, error) {
+	current, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if bytes.Equal(current, updated) {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(current),
+		B:        diffLines(updated),
+		FromFile: file,
+		ToFile:   file + " (compiled)",
+		Context:  3,
+	})
+}
+
+// diffLines splits content into lines keeping their line endings, the last line always ends with a new line
+func diffLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
+
+// diffCommand compares the file with compilation output, without writing anything
+type diffCommand struct {
+	file         string
+	options      saveOptions
+	writer       io.Writer
+	configReader *config.Reader
+}
+
+func newDiffCommand(file string, options saveOptions, writer io.Writer, configReader *config.Reader) *diffCommand {
+	return &diffCommand{
+		file:         file,
+		options:      options,
+		writer:       writer,
+		configReader: configReader,
+	}
+}
+
+// execute prints the diff of the file and compilation output, in dry run it also reports if the file is up to date.
+// Returned bool tells if the file differs from compilation output.
+func (d *diffCommand) execute(dirs []string, hosts []string, showDiff bool, dryRun bool) (bool, error) {
+	buffer := new(bytes.Buffer)
+	compile := newCompileCommand(buffer, d.configReader)
+	compile.render = d.options.render
+	err := compile.execute(dirs, hosts)
+	if err != nil {
+		return false, err
+	}
+	content, err := d.options.outputContent(d.file, buffer.Bytes())
+	if err != nil {
+		return false, err
+	}
+	diff, err := unifiedDiff(d.file, content)
+	if err != nil {
+		return false, err
+	}
+	if showDiff {
+		if _, err = fmt.Fprint(d.writer, diff); err != nil {
+			return false, err
+		}
+	}
+	if dryRun {
+		status := "`%s` is up to date\n"
+		if diff != "" {
+			status = "`%s` differs from compilation output\n"
+		}
+		if _, err = fmt.Fprintf(d.writer, status, d.file); err != nil {
+			return false, err
+		}
+	}
+	return diff != "", nil
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dankraw/ssh-aliases/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldPrintUnifiedDiffOfFile(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(file, []byte("Host a\n     HostName a.example.com\n\nHost b\n     HostName b.example.com\n"), 0o600))
+
+	// when
+	diff, err := unifiedDiff(file, []byte("Host a\n     HostName a.example.com\n\nHost b\n     HostName b2.example.com\n"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "--- "+file+"\n"+
+		"+++ "+file+" (compiled)\n"+
+		"@@ -2,4 +2,4 @@\n"+
+		"      HostName a.example.com\n"+
+		" \n"+
+		" Host b\n"+
+		"-     HostName b.example.com\n"+
+		"+     HostName b2.example.com\n", diff)
+}
+
+func TestShouldNotPrintDiffOfUnchangedFile(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(file, []byte("Host a\n"), 0o600))
+
+	// when
+	diff, err := unifiedDiff(file, []byte("Host a\n"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestDiffCommandShouldReportDrift(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "config")
+	buffer := new(bytes.Buffer)
+
+	// when
+	drift, err := newDiffCommand(file, saveOptions{render: defaultRenderOptions()}, buffer, config.NewReader()).
+		execute([]string{fixtureDir}, []string{}, false, true)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, drift)
+	assert.Equal(t, "`"+file+"` differs from compilation output\n", buffer.String())
+}
+
+func TestDiffCommandShouldReportUpToDateFile(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(fixtureDir, "compile_result")
+	buffer := new(bytes.Buffer)
+
+	// when
+	drift, err := newDiffCommand(file, saveOptions{render: defaultRenderOptions()}, buffer, config.NewReader()).
+		execute([]string{fixtureDir}, []string{}, true, true)
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, drift)
+	assert.Equal(t, "`"+file+"` is up to date\n", buffer.String())
+}
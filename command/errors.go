@@ -0,0 +1,110 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+)
+
+// errorMessage describes err, errors caused by definitions in source files
+// are followed by the source line with a caret pointing the position of the definition
+func errorMessage(err error) string {
+	var diagnostics compiler.Diagnostics
+	if errors.As(err, &diagnostics) {
+		return diagnosticsReport(diagnostics)
+	}
+	var positioned *compiler.Error
+	if !errors.As(err, &positioned) {
+		return err.Error()
+	}
+	snippet := sourceSnippet(positioned.Pos)
+	if snippet == "" {
+		return err.Error()
+	}
+	return err.Error() + "\n" + snippet
+}
+
+// writeWarnings writes the report of diagnostics, if there are any
+func writeWarnings(writer io.Writer, diagnostics compiler.Diagnostics) error {
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(writer, diagnosticsReport(diagnostics))
+	return err
+}
+
+// diagnosticsReport lists diagnostics grouped by their source files, followed by their counts
+func diagnosticsReport(diagnostics compiler.Diagnostics) string {
+	var report strings.Builder
+	file := ""
+	for i, d := range diagnostics.Sorted() {
+		if i == 0 || d.Pos.Filename != file {
+			if i > 0 {
+				report.WriteString("\n")
+			}
+			file = d.Pos.Filename
+			if file != "" {
+				report.WriteString(file + ":\n")
+			}
+		}
+		location := ""
+		if d.Pos.IsValid() {
+			location = fmt.Sprintf("%d:%d: ", d.Pos.Line, d.Pos.Column)
+		}
+		report.WriteString(fmt.Sprintf("  %s%s: %s\n", location, d.Severity, d.Msg))
+		if snippet := sourceSnippet(d.Pos); snippet != "" {
+			report.WriteString("  " + strings.ReplaceAll(snippet, "\n", "\n  ") + "\n")
+		}
+	}
+	report.WriteString("\n" + diagnosticsSummary(diagnostics))
+	return report.String()
+}
+
+func diagnosticsSummary(diagnostics compiler.Diagnostics) string {
+	summary := plural(diagnostics.Count(compiler.SeverityError), "error")
+	if warnings := diagnostics.Count(compiler.SeverityWarning); warnings > 0 {
+		summary += ", " + plural(warnings, "warning")
+	}
+	return summary
+}
+
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+func sourceSnippet(pos compiler.Position) string {
+	if !pos.IsValid() {
+		return ""
+	}
+	data, err := os.ReadFile(pos.Filename)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(data), "\n")
+	if pos.Line > len(lines) {
+		return ""
+	}
+	line := strings.TrimRight(lines[pos.Line-1], "\r")
+	var caret strings.Builder
+	for i, r := range []rune(line) {
+		if i >= pos.Column-1 {
+			break
+		}
+		// tabs are kept, so the caret is aligned regardless of tab width
+		if r == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+	number := fmt.Sprintf("%d", pos.Line)
+	gutter := strings.Repeat(" ", len(number))
+	return fmt.Sprintf(" %s | %s\n %s | %s^", number, line, gutter, caret.String())
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldPrintSourceSnippetOfPositionedErrors(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(fixtureDir, "my_service.hcl")
+	err := compiler.Errorf(compiler.Position{Filename: file, Line: 3, Column: 11}, "something is wrong")
+
+	// when
+	msg := errorMessage(err)
+
+	// then
+	assert.Equal(t, file+":3:11: something is wrong\n"+
+		" 3 |   alias = \"myservice{#1}\"\n"+
+		"   |           ^", msg)
+}
+
+func TestShouldPrintPlainErrorsWithoutSnippet(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []error{
+		errors.New("something is wrong"),
+		compiler.Errorf(compiler.Position{Filename: "not_existing.hcl", Line: 3, Column: 11}, "something is wrong"),
+	}
+
+	for _, err := range entries {
+		// when
+		msg := errorMessage(err)
+
+		// then
+		assert.Equal(t, err.Error(), msg)
+	}
+}
+
+func TestShouldReportDiagnosticsGroupedByFiles(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(fixtureDir, "my_service.hcl")
+	err := compiler.Diagnostics{
+		{Severity: compiler.SeverityError, Pos: compiler.Position{Filename: file, Line: 3, Column: 11}, Msg: "alias error"},
+		{Severity: compiler.SeverityWarning, Pos: compiler.Position{Filename: "vars.hcl", Line: 1, Column: 1}, Msg: "a warning"},
+		{Severity: compiler.SeverityError, Pos: compiler.Position{Filename: file, Line: 1, Column: 1}, Msg: "host error"},
+	}
+
+	// when
+	msg := errorMessage(err)
+
+	// then
+	assert.Equal(t, file+":\n"+
+		"  1:1: error: host error\n"+
+		"   1 | host \"my-service\" {\n"+
+		"     | ^\n"+
+		"  3:11: error: alias error\n"+
+		"   3 |   alias = \"myservice{#1}\"\n"+
+		"     |           ^\n"+
+		"\n"+
+		"vars.hcl:\n"+
+		"  1:1: warning: a warning\n"+
+		"\n"+
+		"2 errors, 1 warning", msg)
+}
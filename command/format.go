@@ -0,0 +1,140 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat selects how compiled hosts are printed
+type outputFormat string
+
+const (
+	formatText outputFormat = "text"
+	formatJSON outputFormat = "json"
+	formatYAML outputFormat = "yaml"
+)
+
+// hostsOutputVersion is the version of the schema of hosts printed in JSON and YAML formats,
+// it is increased whenever a change of the schema could break existing consumers
+const hostsOutputVersion = 1
+
+func parseOutputFormat(value string) (outputFormat, error) {
+	switch f := outputFormat(value); f {
+	case formatText, formatJSON, formatYAML:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid output format `%s`, expected `%s`, `%s` or `%s`", value, formatText, formatJSON, formatYAML)
+}
+
+// compiledHost is a compilation result along with the host definition it was compiled from
+type compiledHost struct {
+	compiler.HostEntity
+	source     string
+	definition string
+	priority   int
+}
+
+// compiledMatch is a match definition along with the source it was read from
+type compiledMatch struct {
+	compiler.MatchConfig
+	source string
+}
+
+type hostsOutput struct {
+	Version int           `json:"version" yaml:"version"`
+	Hosts   []hostOutput  `json:"hosts" yaml:"hosts"`
+	Matches []matchOutput `json:"matches,omitempty" yaml:"matches,omitempty"`
+}
+
+type hostOutput struct {
+	Alias      string           `json:"alias" yaml:"alias"`
+	HostName   string           `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Source     string           `json:"source" yaml:"source"`
+	Definition string           `json:"definition" yaml:"definition"`
+	Properties []propertyOutput `json:"properties" yaml:"properties"`
+}
+
+type matchOutput struct {
+	Name       string            `json:"name" yaml:"name"`
+	Source     string            `json:"source" yaml:"source"`
+	Position   string            `json:"position" yaml:"position"`
+	Criteria   []criterionOutput `json:"criteria" yaml:"criteria"`
+	Properties []propertyOutput  `json:"properties" yaml:"properties"`
+}
+
+type criterionOutput struct {
+	Keyword string `json:"keyword" yaml:"keyword"`
+	Value   string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// propertyOutput keeps values as they are printed in ssh config, so their types do not depend on input files
+type propertyOutput struct {
+	Keyword string `json:"keyword" yaml:"keyword"`
+	Value   string `json:"value" yaml:"value"`
+}
+
+// writeHosts prints compiled hosts and matches in JSON or YAML format
+func writeHosts(writer io.Writer, format outputFormat, result compilation) error {
+	output := hostsOutput{
+		Version: hostsOutputVersion,
+		Hosts:   make([]hostOutput, 0, len(result.hosts)),
+	}
+	for _, h := range result.hosts {
+		host := hostOutput{
+			Alias:      h.Host,
+			HostName:   h.HostName,
+			Source:     h.source,
+			Definition: h.definition,
+			Properties: propertiesOutput(h.Config),
+		}
+		output.Hosts = append(output.Hosts, host)
+	}
+	for _, m := range result.matches {
+		position := "bottom"
+		if m.Top {
+			position = "top"
+		}
+		match := matchOutput{
+			Name:       m.Name,
+			Source:     m.source,
+			Position:   position,
+			Criteria:   make([]criterionOutput, 0, len(m.Criteria)),
+			Properties: propertiesOutput(m.Config),
+		}
+		for _, c := range m.Criteria {
+			match.Criteria = append(match.Criteria, criterionOutput{c.Keyword, c.Value})
+		}
+		output.Matches = append(output.Matches, match)
+	}
+	if format == formatYAML {
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// propertiesOutput lists properties the way they are written in ssh config, a property with a list value
+// is listed once for each of its elements
+func propertiesOutput(config compiler.ConfigProperties) []propertyOutput {
+	properties := make([]propertyOutput, 0, len(config))
+	for _, p := range config {
+		values, ok := p.Value.([]interface{})
+		if !ok {
+			values = []interface{}{p.Value}
+		}
+		for _, v := range values {
+			properties = append(properties, propertyOutput{p.Key, fmt.Sprintf("%v", v)})
+		}
+	}
+	return properties
+}
@@ -0,0 +1,18 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldNotAcceptInvalidOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, err := parseOutputFormat("xml")
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, "invalid output format `xml`, expected `text`, `json` or `yaml`", err.Error())
+}
@@ -4,21 +4,31 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/dankraw/ssh-aliases/compiler"
 )
 
 func readHostsFile(hostsFile string) ([]string, error) {
 	if hostsFile == "" {
 		return []string{}, nil
 	}
-	
-	// Basic path validation
-	if strings.Contains(hostsFile, "..") || strings.HasPrefix(hostsFile, "/") {
-		return nil, fmt.Errorf("invalid hosts file path: %s", hostsFile)
-	}
-	
 	bytes, err := os.ReadFile(hostsFile)
 	if err != nil {
 		return nil, fmt.Errorf("could not read input hosts file: %s: %s", hostsFile, err.Error())
 	}
 	return strings.Split(string(bytes), "\n"), nil
 }
+
+// compileHost compiles a host definition, regexp host definitions are compiled against provided hosts
+// and reported with a warning when none of the hosts matches them
+func compileHost(c *compiler.Compiler, host compiler.ExpandingHostConfig, hosts []string,
+	diagnostics *compiler.Collector) ([]compiler.HostEntity, error) {
+	if !host.IsRegexpHostDefinition() {
+		return c.Compile(host)
+	}
+	results, err := c.CompileRegexp(host, hosts)
+	if err == nil && len(results) == 0 {
+		diagnostics.Warnf(host.HostnamePosition, "regexp host `%s` does not match any of provided hosts", host.AliasName)
+	}
+	return results, err
+}
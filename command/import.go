@@ -0,0 +1,29 @@
+package command
+
+import (
+	"io"
+
+	"github.com/dankraw/ssh-aliases/sshconfig"
+)
+
+type importCommand struct {
+	writer   io.Writer
+	parser   *sshconfig.Parser
+	importer *sshconfig.Importer
+}
+
+func newImportCommand(writer io.Writer, homeDir string) *importCommand {
+	return &importCommand{
+		writer:   writer,
+		parser:   sshconfig.NewParser(homeDir),
+		importer: sshconfig.NewImporter(),
+	}
+}
+
+func (c *importCommand) execute(file string) error {
+	blocks, err := c.parser.ParseFile(file)
+	if err != nil {
+		return err
+	}
+	return c.importer.Import(blocks, c.writer)
+}
@@ -2,36 +2,72 @@ package command
 
 import (
 	"fmt"
-
 	"io"
+	"os"
 
 	"github.com/dankraw/ssh-aliases/compiler"
 	"github.com/dankraw/ssh-aliases/config"
 )
 
 type listCommand struct {
-	writer        io.Writer
-	configReader  *config.Reader
-	configScanner *config.Scanner
-	compiler      *compiler.Compiler
+	writer            io.Writer
+	format            outputFormat
+	diagnosticsWriter io.Writer
+	configReader      *config.Reader
+	configScanner     *config.Scanner
+	compiler          *compiler.Compiler
 }
 
-func newListCommand(writer io.Writer) *listCommand {
+func newListCommand(writer io.Writer, configReader *config.Reader) *listCommand {
 	return &listCommand{
-		writer:        writer,
-		configReader:  config.NewReader(),
-		configScanner: config.NewScanner(),
-		compiler:      compiler.NewCompiler(),
+		writer:            writer,
+		format:            formatText,
+		diagnosticsWriter: os.Stderr,
+		configReader:      configReader,
+		configScanner:     config.NewScanner(),
+		compiler:          compiler.NewCompiler(),
 	}
 }
 
-func (e *listCommand) execute(dir string, hosts []string) error {
-	ctx, err := e.configReader.ReadConfigs(dir)
+func (e *listCommand) execute(dirs []string, hosts []string) error {
+	ctx, diagnostics := e.configReader.CollectConfigs(dirs...)
+	if diagnostics.Stopped() {
+		return diagnostics.Err()
+	}
+	// results of each host definition of each source
+	compiled := make([][][]compiler.HostEntity, len(ctx.Sources))
+	for i, s := range ctx.Sources {
+		for _, h := range s.Hosts {
+			results, err := compileHost(e.compiler, h, hosts, diagnostics)
+			if err != nil {
+				if diagnostics.Add(err) {
+					return diagnostics.Err()
+				}
+				continue
+			}
+			compiled[i] = append(compiled[i], results)
+		}
+	}
+	if err := diagnostics.Err(); err != nil {
+		return err
+	}
+	err := writeWarnings(e.diagnosticsWriter, diagnostics.Diagnostics())
 	if err != nil {
 		return err
 	}
+	if e.format != formatText {
+		var all compilation
+		for i, s := range ctx.Sources {
+			for k, h := range s.Hosts {
+				for _, r := range compiled[i][k] {
+					all.hosts = append(all.hosts, compiledHost{r, s.SourceName, h.AliasName, h.Priority})
+				}
+			}
+		}
+		return writeHosts(e.writer, e.format, all)
+	}
 	j := 0
-	for _, s := range ctx.Sources {
+	for i, s := range ctx.Sources {
 		if len(s.Hosts) < 1 {
 			continue
 		}
@@ -48,11 +84,8 @@ func (e *listCommand) execute(dir string, hosts []string) error {
 		if err != nil {
 			return err
 		}
-		for _, h := range s.Hosts {
-			results, err := e.compileHost(h, hosts)
-			if err != nil {
-				return err
-			}
+		for k, h := range s.Hosts {
+			results := compiled[i][k]
 			_, err = fmt.Fprint(e.writer, "\n "+h.AliasName)
 			if err != nil {
 				return err
@@ -78,10 +111,3 @@ func (e *listCommand) execute(dir string, hosts []string) error {
 	}
 	return nil
 }
-
-func (e *listCommand) compileHost(host compiler.ExpandingHostConfig, hosts []string) ([]compiler.HostEntity, error) {
-	if host.IsRegexpHostDefinition() {
-		return e.compiler.CompileRegexp(host, hosts)
-	}
-	return e.compiler.Compile(host)
-}
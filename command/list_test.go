@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/dankraw/ssh-aliases/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,10 +20,27 @@ func TestListCommandExecute(t *testing.T) {
 	hosts := []string{}
 
 	// when
-	err := newListCommand(buffer).execute(fixtureDir, hosts)
+	err := newListCommand(buffer, config.NewReader()).execute([]string{fixtureDir}, hosts)
 
 	// then
 	assert.NoError(t, err)
 	output, _ := os.ReadFile(filepath.Join(fixtureDir, "list_result"))
 	assert.Equal(t, string(output), buffer.String())
 }
+
+func TestListCommandExecuteInYAMLFormat(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+	list := newListCommand(buffer, config.NewReader())
+	list.format = formatYAML
+
+	// when
+	err := list.execute([]string{fixtureDir}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	output, _ := os.ReadFile(filepath.Join(fixtureDir, "output", "list_result.yaml"))
+	assert.Equal(t, string(output), buffer.String())
+}
@@ -0,0 +1,96 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	managedBlockBegin = "# BEGIN ssh-aliases"
+	managedBlockEnd   = "# END ssh-aliases"
+)
+
+// blockPosition tells where a managed block is inserted into a file that does not contain it yet
+type blockPosition string
+
+const (
+	blockTop    blockPosition = "top"
+	blockBottom blockPosition = "bottom"
+)
+
+func parseBlockPosition(value string) (blockPosition, error) {
+	switch p := blockPosition(value); p {
+	case blockTop, blockBottom:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid block position `%s`, expected `%s` or `%s`", value, blockTop, blockBottom)
+}
+
+// withManagedBlock puts generated config between managed block markers of content,
+// the block is inserted at given position if content does not contain it.
+// Content outside of the block is preserved as it is.
+func withManagedBlock(content []byte, generated []byte, position blockPosition) ([]byte, error) {
+	block := new(bytes.Buffer)
+	block.WriteString(managedBlockBegin + "\n")
+	block.Write(generated)
+	if len(generated) > 0 && !bytes.HasSuffix(generated, []byte("\n")) {
+		block.WriteString("\n")
+	}
+	block.WriteString(managedBlockEnd + "\n")
+
+	begin, end, err := managedBlockBounds(content)
+	if err != nil {
+		return nil, err
+	}
+	result := new(bytes.Buffer)
+	switch {
+	case begin >= 0:
+		result.Write(content[:begin])
+		result.Write(block.Bytes())
+		result.Write(content[end:])
+	case position == blockBottom:
+		result.Write(content)
+		if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+			result.WriteString("\n")
+		}
+		result.Write(block.Bytes())
+	default:
+		result.Write(block.Bytes())
+		result.Write(content)
+	}
+	return result.Bytes(), nil
+}
+
+// managedBlockBounds returns offsets of the beginning of the begin marker line
+// and of the end of the end marker line, or -1 if content does not contain the block
+func managedBlockBounds(content []byte) (int, int, error) {
+	begin, end := -1, -1
+	offset := 0
+	lineNo := 0
+	for offset < len(content) {
+		lineNo++
+		lineEnd := bytes.IndexByte(content[offset:], '\n')
+		next := len(content)
+		if lineEnd >= 0 {
+			next = offset + lineEnd + 1
+		}
+		line := string(bytes.TrimRight(content[offset:next], " \t\r\n"))
+		switch line {
+		case managedBlockBegin:
+			if begin >= 0 {
+				return -1, -1, fmt.Errorf("`%s` marker repeated at line %d", managedBlockBegin, lineNo)
+			}
+			begin = offset
+		case managedBlockEnd:
+			if begin < 0 || end >= 0 {
+				return -1, -1, fmt.Errorf("unexpected `%s` marker at line %d", managedBlockEnd, lineNo)
+			}
+			end = next
+		}
+		offset = next
+	}
+	if begin >= 0 && end < 0 {
+		return -1, -1, fmt.Errorf("`%s` marker is not followed by `%s` marker", managedBlockBegin, managedBlockEnd)
+	}
+	return begin, end, nil
+}
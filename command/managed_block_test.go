@@ -0,0 +1,132 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dankraw/ssh-aliases/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldPutGeneratedConfigIntoManagedBlock(t *testing.T) {
+	t.Parallel()
+
+	// given
+	generated := "Host a\n     HostName a.example.com\n\n"
+	block := "# BEGIN ssh-aliases\n" + generated + "# END ssh-aliases\n"
+	entries := []struct {
+		content  string
+		position blockPosition
+		expected string
+	}{
+		{"", blockTop, block},
+		{"", blockBottom, block},
+		{"Host manual\n  User me\n", blockTop, block + "Host manual\n  User me\n"},
+		{"Host manual\n  User me\n", blockBottom, "Host manual\n  User me\n" + block},
+		{"Host manual\n  User me", blockBottom, "Host manual\n  User me\n" + block},
+		{"Host x\n\n# BEGIN ssh-aliases\nHost old\n# END ssh-aliases\n\nHost *\n  User me", blockBottom,
+			"Host x\n\n" + block + "\nHost *\n  User me"},
+		{"Host x\r\n# BEGIN ssh-aliases\r\nHost old\r\n# END ssh-aliases\r\nHost *\r\n", blockTop,
+			"Host x\r\n" + block + "Host *\r\n"},
+	}
+
+	for _, e := range entries {
+		// when
+		content, err := withManagedBlock([]byte(e.content), []byte(generated), e.position)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, e.expected, string(content))
+	}
+}
+
+func TestShouldNotAcceptInvalidManagedBlock(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		content  string
+		expected string
+	}{
+		{"# BEGIN ssh-aliases\nHost a\n", "`# BEGIN ssh-aliases` marker is not followed by `# END ssh-aliases` marker"},
+		{"Host a\n# END ssh-aliases\n", "unexpected `# END ssh-aliases` marker at line 2"},
+		{"# BEGIN ssh-aliases\n# BEGIN ssh-aliases\n# END ssh-aliases\n", "`# BEGIN ssh-aliases` marker repeated at line 2"},
+		{"# BEGIN ssh-aliases\n# END ssh-aliases\n# END ssh-aliases\n", "unexpected `# END ssh-aliases` marker at line 3"},
+	}
+
+	for _, e := range entries {
+		// when
+		_, err := withManagedBlock([]byte(e.content), []byte{}, blockTop)
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, e.expected, err.Error())
+	}
+}
+
+func TestShouldNotAcceptInvalidBlockPosition(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, err := parseBlockPosition("middle")
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, "invalid block position `middle`, expected `top` or `bottom`", err.Error())
+}
+
+func TestCompileSaveCommandShouldPreserveContentOutsideOfManagedBlock(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "config")
+	manual := "Host manual\n  User me\n"
+	assert.NoError(t, os.WriteFile(file, []byte(manual), 0o600))
+	options := saveOptions{managedBlock: true, blockPosition: blockBottom, render: defaultRenderOptions()}
+
+	// when
+	err := newCompileSaveCommand(file, options, config.NewReader()).execute([]string{fixtureDir}, true, []string{})
+
+	// then
+	assert.NoError(t, err)
+	expected, _ := os.ReadFile(filepath.Join(fixtureDir, "compile_result"))
+	output, _ := os.ReadFile(file)
+	assert.Equal(t, manual+"# BEGIN ssh-aliases\n"+string(expected)+"# END ssh-aliases\n", string(output))
+}
+
+func TestCompileSaveCommandShouldRequireConfirmationOfManagedBlockChanges(t *testing.T) {
+	t.Parallel()
+
+	// given
+	manual := "Host manual\n  User me\n"
+	expected, _ := os.ReadFile(filepath.Join(fixtureDir, "compile_result"))
+	entries := []struct {
+		response string
+		content  string
+	}{
+		{"Y\n", manual + "# BEGIN ssh-aliases\n" + string(expected) + "# END ssh-aliases\n"},
+		{"n\n", manual},
+	}
+
+	for _, e := range entries {
+		file := filepath.Join(t.TempDir(), "config")
+		assert.NoError(t, os.WriteFile(file, []byte(manual), 0o600))
+		options := saveOptions{managedBlock: true, blockPosition: blockBottom, render: defaultRenderOptions()}
+		prompt := new(bytes.Buffer)
+		command := newCompileSaveCommand(file, options, config.NewReader())
+		command.confirm = newConfirm(NewTestReader(e.response))
+		command.confirm.writer = prompt
+
+		// when
+		err := command.execute([]string{fixtureDir}, false, []string{})
+
+		// then
+		assert.NoError(t, err)
+		output, _ := os.ReadFile(file)
+		assert.Equal(t, e.content, string(output), e.response)
+		assert.Contains(t, prompt.String(), "+# BEGIN ssh-aliases\n")
+		assert.Contains(t, prompt.String(), "File `"+file+"` already exists. Overwrite? (Y/n)\n")
+	}
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/keywords"
+)
+
+const defaultIndentWidth = 5
+
+// quotedTypes are types of keywords taking a single argument, values of other keywords (commands, forward specifications,
+// lists of arguments, unknown keywords) are written as they were declared
+var quotedTypes = map[keywords.ValueType]struct{}{
+	keywords.String:   {},
+	keywords.Flag:     {},
+	keywords.Integer:  {},
+	keywords.List:     {},
+	keywords.Duration: {},
+}
+
+// renderOptions tell how compiled hosts are rendered in ssh config
+type renderOptions struct {
+	// indentWidth is the number of spaces, or tabs, that properties are indented with
+	indentWidth int
+	tabs        bool
+	// sourceComments precede each `Host` block with a comment pointing the host definition it was compiled from
+	sourceComments bool
+}
+
+func defaultRenderOptions() renderOptions {
+	return renderOptions{
+		indentWidth: defaultIndentWidth,
+	}
+}
+
+func (o renderOptions) indentation() string {
+	if o.tabs {
+		return strings.Repeat("\t", o.indentWidth)
+	}
+	return strings.Repeat(" ", o.indentWidth)
+}
+
+// renderedValues returns values of the property as they should be written in ssh config,
+// the keyword is repeated for each element of a list
+func renderedValues(keyword string, value interface{}) []string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return []string{renderedValue(keyword, value)}
+	}
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		values = append(values, renderedValue(keyword, v))
+	}
+	return values
+}
+
+// renderedValue returns the value as it should be written in ssh config,
+// values of keywords taking a single argument are quoted when they contain whitespace or `#`
+func renderedValue(keyword string, value interface{}) string {
+	str := fmt.Sprintf("%v", value)
+	known, ok := keywords.Lookup(keyword)
+	if !ok {
+		return str
+	}
+	if _, quoted := quotedTypes[known.Type]; !quoted {
+		return str
+	}
+	return quotedArgument(str)
+}
+
+// quotedArgument quotes the argument when it contains whitespace or `#`, or is empty
+func quotedArgument(str string) string {
+	if !strings.ContainsAny(str, " \t#") && str != "" {
+		return str
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(str) + `"`
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dankraw/ssh-aliases/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldQuoteRenderedValues(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		keyword  string
+		value    interface{}
+		expected string
+	}{
+		{"User", "ubuntu", "ubuntu"},
+		{"Port", 22, "22"},
+		{"IdentityFile", "~/my keys/a.pem", `"~/my keys/a.pem"`},
+		{"IdentityFile", "~/keys/#1.pem", `"~/keys/#1.pem"`},
+		{"CertificateFile", "~/my keys/a-cert.pub", `"~/my keys/a-cert.pub"`},
+		{"ControlPath", `~/.ssh/cm "%r@%h"`, `"~/.ssh/cm \"%r@%h\""`},
+		{"SetEnv", `GREETING="hello world"`, `GREETING="hello world"`},
+		{"SendEnv", "LANG LC_*", "LANG LC_*"},
+		{"IPQoS", "af21 cs1", "af21 cs1"},
+		{"CanonicalDomains", "example.com example.org", "example.com example.org"},
+		{"UserKnownHostsFile", `"~/my hosts" ~/.ssh/known_hosts`, `"~/my hosts" ~/.ssh/known_hosts`},
+		{"UseKeychain", "yes # on macOS", "yes # on macOS"},
+		{"User", "", `""`},
+		{"ProxyCommand", "ssh -W %h:%p bastion # via bastion", "ssh -W %h:%p bastion # via bastion"},
+		{"LocalForward", "8080 localhost:80", "8080 localhost:80"},
+	}
+
+	for _, e := range entries {
+		// when
+		rendered := renderedValue(e.keyword, e.value)
+
+		// then
+		assert.Equal(t, e.expected, rendered, e.keyword)
+	}
+}
+
+func TestShouldRenderListValuesSeparately(t *testing.T) {
+	t.Parallel()
+
+	// when
+	rendered := renderedValues("IdentityFile", []interface{}{"~/.ssh/a.pem", "~/my keys/b.pem"})
+
+	// then
+	assert.Equal(t, []string{"~/.ssh/a.pem", `"~/my keys/b.pem"`}, rendered)
+}
+
+func TestCompileCommandShouldRenderWithOptions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+	compile := newCompileCommand(buffer, config.NewReader())
+	compile.render = renderOptions{indentWidth: 1, tabs: true, sourceComments: true}
+
+	// when
+	err := compile.execute([]string{fixtureDir}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(buffer.String(), "# from test-fixtures/consul_and_frontend.hcl:consul\n"+
+		"Host consul1-dc1\n"+
+		"\tHostName consul1.dc1.example.com\n"+
+		"\tIdentityFile some_file.pem\n"+
+		"\tUser ubuntu\n"+
+		"\n"+
+		"# from test-fixtures/consul_and_frontend.hcl:consul\n"+
+		"Host consul2-dc1\n"), buffer.String())
+}
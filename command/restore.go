@@ -0,0 +1,91 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+type restoreCommand struct {
+	file    string
+	writer  io.Writer
+	backups *backups
+}
+
+func newRestoreCommand(file string, writer io.Writer) *restoreCommand {
+	return &restoreCommand{
+		file:    file,
+		writer:  writer,
+		backups: newBackups(file),
+	}
+}
+
+// execute lists backups of the file when selected backup is empty, otherwise it replaces the file
+// with the backup selected by its number on the list or its file name.
+// Replaced content of the file is backed up as well, so restoring can be undone, and the restored backup
+// is never removed by the limit of backups.
+func (r *restoreCommand) execute(selected string, keep int) error {
+	existing, err := r.backups.list()
+	if err != nil {
+		return err
+	}
+	if selected == "" {
+		return r.printBackups(existing)
+	}
+	b, err := r.selectBackup(existing, selected)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(b.path)
+	if err != nil {
+		return err
+	}
+	if keep > 0 {
+		// the restored backup is kept, so it is not lost when writing the file fails
+		if err = r.backups.create(keep, b.path); err != nil {
+			return err
+		}
+	}
+	if err = writeFileAtomically(r.file, content); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.writer, "Restored `%s` from backup created at %s\n", r.file, b.created.Format(time.DateTime))
+	return err
+}
+
+func (r *restoreCommand) printBackups(existing []backup) error {
+	if len(existing) == 0 {
+		_, err := fmt.Fprintf(r.writer, "No backups of `%s` found\n", r.file)
+		return err
+	}
+	_, err := fmt.Fprintf(r.writer, "Backups of `%s`, the newest first:\n", r.file)
+	if err != nil {
+		return err
+	}
+	for i, b := range existing {
+		_, err = fmt.Fprintf(r.writer, " %d. %s (%s)\n", i+1, b.created.Format(time.DateTime), filepath.Base(b.path))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *restoreCommand) selectBackup(existing []backup, selected string) (backup, error) {
+	if n, err := strconv.Atoi(selected); err == nil {
+		if n < 1 || n > len(existing) {
+			return backup{}, fmt.Errorf("backup number %d out of range, %s of `%s` found",
+				n, plural(len(existing), "backup"), r.file)
+		}
+		return existing[n-1], nil
+	}
+	for _, b := range existing {
+		if filepath.Base(b.path) == filepath.Base(selected) {
+			return b, nil
+		}
+	}
+	return backup{}, fmt.Errorf("backup `%s` of `%s` not found", selected, r.file)
+}
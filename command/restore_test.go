@@ -0,0 +1,121 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func restoreFixture(t *testing.T) (string, *restoreCommand, *bytes.Buffer) {
+	file := filepath.Join(t.TempDir(), "config")
+	buffer := new(bytes.Buffer)
+	restore := newRestoreCommand(file, buffer)
+	restore.backups.now = fixedClock()
+	for _, content := range []string{"first", "second", "current"} {
+		assert.NoError(t, restore.backups.create(5))
+		assert.NoError(t, writeFileAtomically(file, []byte(content)))
+	}
+	return file, restore, buffer
+}
+
+func TestRestoreCommandShouldListBackups(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file, restore, buffer := restoreFixture(t)
+
+	// when
+	err := restore.execute("", 5)
+
+	// then
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(), "Backups of `"+file+"`, the newest first:\n")
+	assert.Contains(t, buffer.String(), " 1. ")
+	assert.Contains(t, buffer.String(), " 2. ")
+	assert.NotContains(t, buffer.String(), " 3. ")
+}
+
+func TestRestoreCommandShouldRestoreSelectedBackup(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		selected func(existing []backup) string
+		expected string
+	}{
+		{func(_ []backup) string { return "2" }, "first"},
+		{func(existing []backup) string { return filepath.Base(existing[0].path) }, "second"},
+	}
+
+	for _, e := range entries {
+		file, restore, _ := restoreFixture(t)
+		existing, _ := restore.backups.list()
+
+		// when
+		err := restore.execute(e.selected(existing), 5)
+
+		// then
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(file)
+		assert.Equal(t, e.expected, string(content))
+	}
+}
+
+func TestRestoreCommandShouldBackupRestoredFile(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file, restore, _ := restoreFixture(t)
+
+	// when
+	err := restore.execute("2", 5)
+
+	// then
+	assert.NoError(t, err)
+	existing, _ := restore.backups.list()
+	assert.Len(t, existing, 3)
+	content, _ := os.ReadFile(existing[0].path)
+	assert.Equal(t, "current", string(content))
+	content, _ = os.ReadFile(file)
+	assert.Equal(t, "first", string(content))
+}
+
+func TestRestoreCommandShouldKeepRestoredBackupAtLimit(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file, restore, _ := restoreFixture(t)
+	existing, _ := restore.backups.list()
+	restored := existing[1].path
+
+	// when
+	err := restore.execute("2", 1)
+
+	// then
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(file)
+	assert.Equal(t, "first", string(content))
+	existing, _ = restore.backups.list()
+	assert.Len(t, existing, 2)
+	content, _ = os.ReadFile(existing[0].path)
+	assert.Equal(t, "current", string(content))
+	assert.Equal(t, restored, existing[1].path)
+}
+
+func TestRestoreCommandShouldNotRestoreUnknownBackup(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file, restore, _ := restoreFixture(t)
+
+	// when
+	errByNumber := restore.execute("3", 5)
+	errByName := restore.execute("config.bak", 5)
+
+	// then
+	assert.EqualError(t, errByNumber, "backup number 3 out of range, 2 backups of `"+file+"` found")
+	assert.EqualError(t, errByName, "backup `config.bak` of `"+file+"` not found")
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultBackupsLimit = 5
+
+// saveOptions tell how compiled config is written to the output file
+type saveOptions struct {
+	// managedBlock makes compiled config replace only the content between managed block markers,
+	// instead of the whole file
+	managedBlock  bool
+	blockPosition blockPosition
+	// diff prints changes of the file before it is written, even if overwriting does not require a confirmation
+	diff   bool
+	render renderOptions
+	// backups is the number of backups of previous output file contents that are kept, 0 disables backups
+	backups int
+}
+
+// outputContent returns the new content of the output file containing generated config
+func (o saveOptions) outputContent(file string, generated []byte) ([]byte, error) {
+	if !o.managedBlock {
+		return generated, nil
+	}
+	current, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	content, err := withManagedBlock(current, generated, o.blockPosition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid managed block in `%s`: %s", file, err.Error())
+	}
+	return content, nil
+}
+
+// write backs up the current content of the file and atomically replaces it with provided content
+func (o saveOptions) write(file string, content []byte) error {
+	if o.backups > 0 {
+		if err := newBackups(file).create(o.backups); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomically(file, content)
+}
+
+// writeFileAtomically writes content into a temporary file placed next to the target file and renames it into place,
+// so the target file is never left partially written. Permissions of an existing target file are preserved
+// and symlinked target files are resolved, so that the link is kept.
+func writeFileAtomically(file string, content []byte) error {
+	mode := os.FileMode(0o600)
+	if resolved, err := filepath.EvalSymlinks(file); err == nil {
+		file = resolved
+	}
+	info, err := os.Stat(file)
+	switch {
+	case err == nil:
+		mode = info.Mode().Perm()
+	case !os.IsNotExist(err):
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %s", err.Error())
+	}
+	// removing fails once the file is renamed, which is expected
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write temporary file `%s`: %s", tmp.Name(), err.Error())
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write temporary file `%s`: %s", tmp.Name(), err.Error())
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("could not write temporary file `%s`: %s", tmp.Name(), err.Error())
+	}
+	if err = os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldWriteFileAtomicallyPreservingPermissions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config")
+	assert.NoError(t, os.WriteFile(file, []byte("old"), 0o640))
+	assert.NoError(t, os.Chmod(file, 0o640))
+
+	// when
+	err := writeFileAtomically(file, []byte("new"))
+
+	// then
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(file)
+	assert.Equal(t, "new", string(content))
+	info, _ := os.Stat(file)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 1)
+}
+
+func TestShouldWriteNewFileAtomically(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "config")
+
+	// when
+	err := writeFileAtomically(file, []byte("new"))
+
+	// then
+	assert.NoError(t, err)
+	info, _ := os.Stat(file)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestShouldKeepSymlinkWhenWritingAtomically(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	link := filepath.Join(dir, "config")
+	assert.NoError(t, os.WriteFile(target, []byte("old"), 0o600))
+	assert.NoError(t, os.Symlink(target, link))
+
+	// when
+	err := writeFileAtomically(link, []byte("new"))
+
+	// then
+	assert.NoError(t, err)
+	info, _ := os.Lstat(link)
+	assert.Equal(t, os.ModeSymlink, info.Mode()&os.ModeSymlink)
+	content, _ := os.ReadFile(target)
+	assert.Equal(t, "new", string(content))
+}
@@ -0,0 +1,26 @@
+host "defaults" {
+  alias = "*"
+  priority = -1
+  config {
+    user = "ubuntu"
+  }
+}
+
+host "internal" {
+  alias = "*.internal"
+  priority = 1
+  config {
+    proxy_jump = "jump"
+  }
+}
+
+host "app" {
+  hostname = "app.example.com"
+  alias = "app"
+}
+
+host "jump" {
+  hostname = "jump.example.com"
+  alias = "jump"
+  priority = 1
+}
@@ -0,0 +1,10 @@
+host "service-a" {
+  hostname = "service-a.example.com"
+  alias = "service-a"
+  config = "missing"
+}
+
+host "service-b" {
+  hostname = "service-b[1..2].example.com"
+  alias = "service-b{#2}"
+}
@@ -0,0 +1,21 @@
+host "app" {
+  hostname = "app.example.com"
+  alias = "app"
+}
+
+match "production" {
+  host = ["*.prod.example.com", "prod-*"]
+  user = "deploy"
+  config {
+    identity_file = "~/.ssh/deploy key.pem"
+  }
+}
+
+match "canonical" {
+  canonical = true
+  all = true
+  position = "top"
+  config {
+    forward_agent = "no"
+  }
+}
@@ -0,0 +1,18 @@
+host "tunnel" {
+  hostname = "tunnel.example.com"
+  alias = "tunnel"
+  config {
+    identity_file = ["~/.ssh/tunnel.pem", "~/.ssh/${key}.pem"]
+    local_forward = [
+      { bind = 8080, host = "localhost", port = 80 },
+      { bind_address = "127.0.0.1", bind = 5432, host = "db.internal", port = 5432 },
+    ]
+    remote_forward = { bind = 9000, host = "localhost", port = 9000 }
+    dynamic_forward = { bind = 1080 }
+    send_env = ["LANG", "LC_*"]
+  }
+}
+
+var {
+  key = "fallback"
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+	"github.com/dankraw/ssh-aliases/config"
+)
+
+const (
+	// validationFailedExitCode is returned when input definitions contain errors
+	validationFailedExitCode = 1
+	// validationNotPerformedExitCode is returned when validation could not be run, for example due to unreadable hosts file
+	validationNotPerformedExitCode = 2
+)
+
+type validateCommand struct {
+	writer       io.Writer
+	jsonOutput   bool
+	configReader *config.Reader
+	compiler     *compiler.Compiler
+	validator    *compiler.Validator
+}
+
+func newValidateCommand(writer io.Writer, jsonOutput bool, configReader *config.Reader) *validateCommand {
+	return &validateCommand{
+		writer:       writer,
+		jsonOutput:   jsonOutput,
+		configReader: configReader,
+		compiler:     compiler.NewCompiler(),
+		validator:    compiler.NewValidator(),
+	}
+}
+
+type validationReport struct {
+	Valid       bool                   `json:"valid"`
+	Hosts       int                    `json:"hosts"`
+	Errors      int                    `json:"errors"`
+	Warnings    int                    `json:"warnings"`
+	Diagnostics []validationDiagnostic `json:"diagnostics"`
+}
+
+type validationDiagnostic struct {
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+}
+
+// execute compiles all definitions the same way compile command does, without writing any ssh config,
+// and reports found problems, returned bool tells if definitions are free of errors
+func (c *validateCommand) execute(dirs []string, hosts []string) (bool, error) {
+	result, collector := compileAll(c.configReader, c.compiler, c.validator, dirs, hosts)
+	diagnostics := collector.Diagnostics().Sorted()
+	report := validationReport{
+		Valid:       diagnostics.Count(compiler.SeverityError) == 0,
+		Hosts:       len(result.hosts),
+		Errors:      diagnostics.Count(compiler.SeverityError),
+		Warnings:    diagnostics.Count(compiler.SeverityWarning),
+		Diagnostics: []validationDiagnostic{},
+	}
+	for _, d := range diagnostics {
+		report.Diagnostics = append(report.Diagnostics, validationDiagnostic{
+			Severity: d.Severity.String(),
+			File:     d.Pos.Filename,
+			Line:     d.Pos.Line,
+			Column:   d.Pos.Column,
+			Message:  d.Msg,
+		})
+	}
+	if c.jsonOutput {
+		encoder := json.NewEncoder(c.writer)
+		encoder.SetIndent("", "  ")
+		return report.Valid, encoder.Encode(report)
+	}
+	if len(diagnostics) > 0 {
+		if _, err := fmt.Fprintln(c.writer, diagnosticsReport(diagnostics)); err != nil {
+			return false, err
+		}
+	}
+	if !report.Valid {
+		return false, nil
+	}
+	_, err := fmt.Fprintf(c.writer, "Configuration is valid, %s compiled\n", plural(report.Hosts, "host"))
+	return true, err
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/dankraw/ssh-aliases/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCommandShouldAcceptValidDefinitions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+
+	// when
+	valid, err := newValidateCommand(buffer, false, config.NewReader()).execute([]string{fixtureDir}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, "Configuration is valid, 10 hosts compiled\n", buffer.String())
+}
+
+func TestValidateCommandShouldReportAllErrors(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+	file := filepath.Join(fixtureDir, "invalid", "hosts.hcl")
+
+	// when
+	valid, err := newValidateCommand(buffer, false, config.NewReader()).
+		execute([]string{filepath.Join(fixtureDir, "invalid")}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Equal(t, file+":\n"+
+		"  4:12: error: error in `service-a` host definition: no config `missing` found\n"+
+		"   4 |   config = \"missing\"\n"+
+		"     |            ^\n"+
+		"  9:11: error: error compiling host `service-b`: alias `service-b{#2}` contains placeholder with index `#2` "+
+		"being out of bounds, `service-b[1..2].example.com` allows `#1` as the maximum index\n"+
+		"   9 |   alias = \"service-b{#2}\"\n"+
+		"     |           ^\n"+
+		"\n"+
+		"2 errors\n", buffer.String())
+}
+
+func TestValidateCommandShouldPrintJSONDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	// given
+	buffer := new(bytes.Buffer)
+
+	// when
+	valid, err := newValidateCommand(buffer, true, config.NewReader()).
+		execute([]string{filepath.Join(fixtureDir, "invalid")}, []string{})
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	var report validationReport
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &report))
+	assert.False(t, report.Valid)
+	assert.Equal(t, 2, report.Errors)
+	assert.Equal(t, 0, report.Warnings)
+	assert.Equal(t, validationDiagnostic{
+		Severity: "error",
+		File:     filepath.Join(fixtureDir, "invalid", "hosts.hcl"),
+		Line:     4,
+		Column:   12,
+		Message:  "error in `service-a` host definition: no config `missing` found",
+	}, report.Diagnostics[0])
+}
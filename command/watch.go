@@ -0,0 +1,167 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dankraw/ssh-aliases/config"
+)
+
+const (
+	defaultWatchInterval = 500 * time.Millisecond
+	defaultWatchDebounce = 1 * time.Second
+)
+
+// watchCommand recompiles the output file whenever files in scanned dirs or the hosts file change.
+// Changes are detected by polling modification times and sizes of watched files,
+// a burst of changes triggers a single compilation once files stop changing for the debounce period.
+type watchCommand struct {
+	file         string
+	options      saveOptions
+	interval     time.Duration
+	debounce     time.Duration
+	writer       io.Writer
+	errWriter    io.Writer
+	configReader *config.Reader
+}
+
+func newWatchCommand(file string, options saveOptions, debounce time.Duration, writer io.Writer,
+	configReader *config.Reader) *watchCommand {
+	return &watchCommand{
+		file:         file,
+		options:      options,
+		interval:     defaultWatchInterval,
+		debounce:     debounce,
+		writer:       writer,
+		errWriter:    os.Stderr,
+		configReader: configReader,
+	}
+}
+
+// execute compiles the output file and keeps recompiling it on changes, until stop is closed.
+// Failed compilations are reported and the last successfully compiled file is kept untouched.
+func (w *watchCommand) execute(dirs []string, hostsFile string, stop <-chan struct{}) error {
+	watched := append([]string{}, dirs...)
+	if hostsFile != "" {
+		watched = append(watched, hostsFile)
+	}
+	fmt.Fprintf(w.writer, "Watching %s for changes\n", strings.Join(quoted(watched), ", "))
+	last := snapshot(watched)
+	if err := w.recompile(dirs, hostsFile); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	pending := false
+	var changedAt time.Time
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			current := snapshot(watched)
+			if current != last {
+				last = current
+				pending = true
+				changedAt = now
+				continue
+			}
+			if pending && now.Sub(changedAt) >= w.debounce {
+				pending = false
+				if err := w.recompile(dirs, hostsFile); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// recompile writes the output file if compilation succeeds and changes its content,
+// only errors of writing the output file are returned
+func (w *watchCommand) recompile(dirs []string, hostsFile string) error {
+	hosts, err := readHostsFile(hostsFile)
+	if err != nil {
+		w.reportFailure(err)
+		return nil
+	}
+	buffer := new(bytes.Buffer)
+	compile := newCompileCommand(buffer, w.configReader)
+	compile.diagnosticsWriter = w.errWriter
+	compile.render = w.options.render
+	if err = compile.execute(dirs, hosts); err != nil {
+		w.reportFailure(err)
+		return nil
+	}
+	content, err := w.options.outputContent(w.file, buffer.Bytes())
+	if err != nil {
+		w.reportFailure(err)
+		return nil
+	}
+	current, err := os.ReadFile(w.file)
+	if err == nil && bytes.Equal(current, content) {
+		fmt.Fprintf(w.writer, "%s compiled without changes\n", timestamp())
+		return nil
+	}
+	if err = w.options.write(w.file, content); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.writer, "%s written to `%s`\n", timestamp(), w.file)
+	return nil
+}
+
+func (w *watchCommand) reportFailure(err error) {
+	fmt.Fprintf(w.errWriter, "%s compilation failed, keeping the last good `%s`:\n%s\n",
+		timestamp(), w.file, errorMessage(err))
+}
+
+func timestamp() string {
+	return time.Now().Format("15:04:05")
+}
+
+// snapshot describes the state of all files found in watched paths,
+// paths that can not be read are described by their errors
+func snapshot(paths []string) string {
+	var states []string
+	for _, p := range paths {
+		// symlinked directories are resolved first, as walking does not follow links
+		root, err := filepath.EvalSymlinks(p)
+		if err != nil {
+			states = append(states, fmt.Sprintf("%s %s", p, err.Error()))
+			continue
+		}
+		err = filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			states = append(states, fmt.Sprintf("%s %d %d", file, info.Size(), info.ModTime().UnixNano()))
+			return nil
+		})
+		if err != nil {
+			states = append(states, fmt.Sprintf("%s %s", p, err.Error()))
+		}
+	}
+	sort.Strings(states)
+	return strings.Join(states, "\n")
+}
+
+func quoted(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		result = append(result, "`"+v+"`")
+	}
+	return result
+}
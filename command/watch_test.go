@@ -0,0 +1,87 @@
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dankraw/ssh-aliases/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.String()
+}
+
+func TestWatchCommandShouldRecompileOnChangesAndKeepLastGoodFile(t *testing.T) {
+	t.Parallel()
+
+	// given
+	dir := t.TempDir()
+	input := filepath.Join(dir, "hosts.hcl")
+	file := filepath.Join(t.TempDir(), "config")
+	writeInput := func(content string) {
+		assert.NoError(t, os.WriteFile(input, []byte(content), 0o600))
+		// modification time has to differ from the previous write
+		assert.NoError(t, os.Chtimes(input, time.Now(), time.Now().Add(time.Duration(len(content))*time.Second)))
+	}
+	readOutput := func() string {
+		output, _ := os.ReadFile(file)
+		return string(output)
+	}
+	writeInput("host \"a\" {\n  hostname = \"a.example.com\"\n  alias = \"a\"\n}\n")
+	errWriter := &syncBuffer{}
+	watch := newWatchCommand(file, saveOptions{render: defaultRenderOptions()}, 20*time.Millisecond, &syncBuffer{}, config.NewReader())
+	watch.interval = 5 * time.Millisecond
+	watch.errWriter = errWriter
+	stop := make(chan struct{})
+	done := make(chan error)
+
+	// when
+	go func() {
+		done <- watch.execute([]string{dir}, "", stop)
+	}()
+
+	// then
+	assert.Eventually(t, func() bool {
+		return readOutput() == "Host a\n     HostName a.example.com\n\n"
+	}, time.Second, 5*time.Millisecond)
+
+	// when
+	writeInput("host \"b\" {\n  hostname = \"b.example.com\"\n  alias = \"b\"\n}\n")
+
+	// then
+	assert.Eventually(t, func() bool {
+		return readOutput() == "Host b\n     HostName b.example.com\n\n"
+	}, time.Second, 5*time.Millisecond)
+
+	// when
+	writeInput("host \"c\" {\n  hostname = \"c.example.com\"\n  alias = \"c\"\n  config = \"missing\"\n}\n")
+
+	// then
+	assert.Eventually(t, func() bool {
+		return strings.Contains(errWriter.String(), "no config `missing` found")
+	}, time.Second, 5*time.Millisecond)
+	assert.Contains(t, errWriter.String(), "compilation failed, keeping the last good `"+file+"`")
+	assert.Equal(t, "Host b\n     HostName b.example.com\n\n", readOutput())
+
+	close(stop)
+	assert.NoError(t, <-done)
+}
@@ -17,7 +17,7 @@ type Compiler struct {
 func NewCompiler() *Compiler {
 	return &Compiler{
 		expander:     newExpander(),
-		groupsRegexp: regexp.MustCompile(`{#(\d+)}`),
+		groupsRegexp: regexp.MustCompile(`{#(\d+)((?:\|[^|{}]*)*)}`),
 	}
 }
 
@@ -25,6 +25,7 @@ type templateReplacement struct {
 	beginIdx       int
 	endIdx         int
 	replacementIdx int
+	filters        []filter
 }
 
 // Compile converts a single ExpandingHostConfig into list of HostEntities
@@ -43,14 +44,17 @@ func (c *Compiler) Compile(input ExpandingHostConfig) ([]HostEntity, error) {
 	}
 	expanded, err := c.expander.expand(input.HostnamePattern)
 	if err != nil {
-		return nil, err
+		return nil, &Error{Pos: input.HostnamePosition, Msg: err.Error()}
+	}
+	replacements, err := c.aliasReplacementGroups(input.AliasTemplate)
+	if err != nil {
+		return nil, Errorf(input.AliasPosition, "error compiling host `%s`: %s", input.AliasName, err.Error())
 	}
-	replacements := c.aliasReplacementGroups(input.AliasTemplate)
 	var results = make([]HostEntity, 0, len(expanded))
 	for _, h := range expanded {
 		alias, err := c.compileToTargetHost(input.AliasTemplate, replacements, h, input.HostnamePattern)
 		if err != nil {
-			return nil, fmt.Errorf("error compiling host `%s`: %s", input.AliasName, err.Error())
+			return nil, Errorf(input.AliasPosition, "error compiling host `%s`: %s", input.AliasName, err.Error())
 		}
 		results = append(results, HostEntity{
 			Host:     alias,
@@ -76,7 +80,11 @@ func (c *Compiler) compileToTargetHost(aliasTemplate string, replacements []temp
 			alias += aliasTemplate[0:s.beginIdx]
 		}
 
-		alias += host.Replacements[s.replacementIdx]
+		value := host.Replacements[s.replacementIdx]
+		for _, f := range s.filters {
+			value = f(value)
+		}
+		alias += value
 		nextIdx := i + 1
 		if nextIdx < len(replacements) {
 			nextSelector := replacements[nextIdx]
@@ -106,9 +114,12 @@ func (c *Compiler) validateReplacements(aliasTemplate string, hostnamePattern st
 func (c *Compiler) CompileRegexp(input ExpandingHostConfig, hosts InputHosts) ([]HostEntity, error) {
 	re, err := regexp.Compile(input.HostnamePattern)
 	if err != nil {
-		return nil, fmt.Errorf("error compiling hostname pattern of %s: %s", input.AliasName, err.Error())
+		return nil, Errorf(input.HostnamePosition, "error compiling hostname pattern of %s: %s", input.AliasName, err.Error())
+	}
+	replacements, err := c.aliasReplacementGroups(input.AliasTemplate)
+	if err != nil {
+		return nil, Errorf(input.AliasPosition, "error compiling regexp host `%s`: %s", input.AliasName, err.Error())
 	}
-	replacements := c.aliasReplacementGroups(input.AliasTemplate)
 	var results []HostEntity
 	for _, host := range hosts {
 		match := re.FindAllStringSubmatch(host, -1)
@@ -119,7 +130,7 @@ func (c *Compiler) CompileRegexp(input ExpandingHostConfig, hosts InputHosts) ([
 			}
 			alias, err := c.compileToTargetHost(input.AliasTemplate, replacements, h, input.HostnamePattern)
 			if err != nil {
-				return nil, fmt.Errorf("error compiling regexp host `%s`: %s", input.AliasName, err.Error())
+				return nil, Errorf(input.AliasPosition, "error compiling regexp host `%s`: %s", input.AliasName, err.Error())
 			}
 			results = append(results, HostEntity{
 				Host:     alias,
@@ -131,12 +142,18 @@ func (c *Compiler) CompileRegexp(input ExpandingHostConfig, hosts InputHosts) ([
 	return results, nil
 }
 
-func (c *Compiler) aliasReplacementGroups(aliasTemplate string) []templateReplacement {
+// aliasReplacementGroups finds placeholders `{#n}` in an alias template, a placeholder may be followed
+// by a pipeline of filters transforming the value, like `{#1|trim_prefix:web-|upper}`
+func (c *Compiler) aliasReplacementGroups(aliasTemplate string) ([]templateReplacement, error) {
 	templateGroups := c.groupsRegexp.FindAllStringSubmatchIndex(aliasTemplate, -1)
 	var replacements = make([]templateReplacement, 0, len(templateGroups))
 	for _, group := range templateGroups {
 		hostnameGroupSelect, _ := strconv.Atoi(aliasTemplate[group[2]:group[3]])
-		replacements = append(replacements, templateReplacement{group[0], group[1], hostnameGroupSelect - 1})
+		filters, err := parseFilters(aliasTemplate[group[4]:group[5]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder `%s`: %s", aliasTemplate[group[0]:group[1]], err.Error())
+		}
+		replacements = append(replacements, templateReplacement{group[0], group[1], hostnameGroupSelect - 1, filters})
 	}
-	return replacements
+	return replacements, nil
 }
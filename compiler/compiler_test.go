@@ -77,6 +77,139 @@ func TestShouldExpandHostnameWithProvidedRange(t *testing.T) {
 	}}, results)
 }
 
+func TestShouldPassZeroPaddedRangeValuesToAliases(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := ExpandingHostConfig{
+		HostnamePattern: "node[09..10].example.com",
+		AliasTemplate:   "n{#1}",
+	}
+
+	// when
+	results, err := NewCompiler().Compile(input)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []HostEntity{{
+		Host:     "n09",
+		HostName: "node09.example.com",
+	}, {
+		Host:     "n10",
+		HostName: "node10.example.com",
+	}}, results)
+}
+
+func TestShouldPassAlphabeticRangeValuesToAliases(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := ExpandingHostConfig{
+		HostnamePattern: "db[1..2].zone-[a..b].example.com",
+		AliasTemplate:   "db{#1}{#2}",
+	}
+
+	// when
+	results, err := NewCompiler().Compile(input)
+
+	// then
+	assert.NoError(t, err)
+	var aliases []string
+	for _, r := range results {
+		aliases = append(aliases, r.Host)
+	}
+	assert.Equal(t, []string{"db1a", "db2a", "db1b", "db2b"}, aliases)
+}
+
+func TestShouldPassCompoundRangeValuesToAliases(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := ExpandingHostConfig{
+		HostnamePattern: "node[1..4,!2,9].example.com",
+		AliasTemplate:   "n{#1}",
+	}
+
+	// when
+	results, err := NewCompiler().Compile(input)
+
+	// then
+	assert.NoError(t, err)
+	var aliases []string
+	for _, r := range results {
+		aliases = append(aliases, r.Host)
+	}
+	assert.Equal(t, []string{"n1", "n3", "n4", "n9"}, aliases)
+}
+
+func TestShouldApplyFiltersToAliasPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := ExpandingHostConfig{
+		HostnamePattern: "my-service-[api|web].node[1..2].[prod|test].example.com",
+		AliasTemplate:   "{#1|upper}{#2|pad:3}.{#3|first:1} {#1}",
+	}
+
+	// when
+	results, err := NewCompiler().Compile(input)
+
+	// then
+	assert.NoError(t, err)
+	var aliases []string
+	for _, r := range results {
+		aliases = append(aliases, r.Host)
+	}
+	assert.Equal(t, []string{
+		"API001.p api", "WEB001.p web", "API002.p api", "WEB002.p web",
+		"API001.t api", "WEB001.t web", "API002.t api", "WEB002.t web",
+	}, aliases)
+}
+
+func TestShouldApplyFiltersToRegexpAliasPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := ExpandingHostConfig{
+		HostnamePattern: `(my-service-\w+)\.(\d+\.\d+)\.example\.com`,
+		AliasTemplate:   "{#1|trim_prefix:my-service-}-{#2|replace:.:-}",
+	}
+	hosts := InputHosts{"my-service-api.1.2.example.com", "other.example.com"}
+
+	// when
+	results, err := NewCompiler().CompileRegexp(input, hosts)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []HostEntity{{
+		Host:     "api-1-2",
+		HostName: "my-service-api.1.2.example.com",
+	}}, results)
+}
+
+func TestShouldReturnErrorOnUnknownAliasFilter(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := ExpandingHostConfig{
+		AliasName:       "web",
+		HostnamePattern: "web[1..2].example.com",
+		AliasTemplate:   "web{#1|capitalize}",
+	}
+
+	// when
+	results, err := NewCompiler().Compile(input)
+	_, regexpErr := NewCompiler().CompileRegexp(input, InputHosts{})
+
+	// then
+	assert.Nil(t, results)
+	assert.EqualError(t, err, "error compiling host `web`: invalid placeholder `{#1|capitalize}`: unknown filter "+
+		"`capitalize`, expected one of `first`, `lower`, `pad`, `replace`, `trim_prefix`, `trim_suffix`, `upper`")
+	assert.EqualError(t, regexpErr, "error compiling regexp host `web`: invalid placeholder `{#1|capitalize}`: "+
+		"unknown filter `capitalize`, expected one of `first`, `lower`, `pad`, `replace`, `trim_prefix`, "+
+		"`trim_suffix`, `upper`")
+}
+
 func TestShouldAllowUnderscoreInHostname(t *testing.T) {
 	t.Parallel()
 
@@ -237,3 +370,25 @@ func TestCompileWithInvalidAlias(t *testing.T) {
 		"placeholder with index `#2` being out of bounds, `instance[1..2].example.com` allows `#1` as the maximum index",
 		err.Error())
 }
+
+func TestCompileErrorsPointPositionsOfHostDefinition(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := ExpandingHostConfig{
+		AliasName:        "InvalidAlias",
+		HostnamePattern:  "instance[1..2].example.com",
+		AliasTemplate:    "host{#2}",
+		HostnamePosition: Position{Filename: "example.hcl", Line: 2, Column: 14},
+		AliasPosition:    Position{Filename: "example.hcl", Line: 3, Column: 11},
+	}
+
+	// when
+	_, err := NewCompiler().Compile(input)
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, "example.hcl:3:11: error compiling host `InvalidAlias`: alias `host{#2}` contains "+
+		"placeholder with index `#2` being out of bounds, `instance[1..2].example.com` allows `#1` as the maximum index",
+		err.Error())
+}
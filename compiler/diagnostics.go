@@ -0,0 +1,145 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity tells if a Diagnostic prevents generating ssh config
+type Severity int
+
+const (
+	// SeverityError marks problems that prevent generating ssh config
+	SeverityError Severity = iota
+	// SeverityWarning marks problems that do not prevent generating ssh config
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Diagnostic is a single problem found in input definitions
+type Diagnostic struct {
+	Severity Severity
+	Pos      Position
+	Msg      string
+}
+
+func (d Diagnostic) String() string {
+	msg := d.Msg
+	if d.Severity == SeverityWarning {
+		msg = "warning: " + msg
+	}
+	if pos := d.Pos.String(); pos != "" {
+		return pos + ": " + msg
+	}
+	return msg
+}
+
+// Diagnostics is a list of problems found in input definitions, one per line when used as an error
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, 0, len(d))
+	for _, diagnostic := range d {
+		lines = append(lines, diagnostic.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Count returns the number of diagnostics with the given severity
+func (d Diagnostics) Count(severity Severity) int {
+	count := 0
+	for _, diagnostic := range d {
+		if diagnostic.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Sorted returns diagnostics ordered by their files, lines and columns,
+// diagnostics without a position go first
+func (d Diagnostics) Sorted() Diagnostics {
+	sorted := make(Diagnostics, len(d))
+	copy(sorted, d)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Pos, sorted[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return sorted
+}
+
+// Collector gathers diagnostics of all processed definitions,
+// unless it is configured to stop at the first error
+type Collector struct {
+	failFast    bool
+	first       error
+	diagnostics Diagnostics
+}
+
+// NewCollector creates an instance of Collector
+func NewCollector(failFast bool) *Collector {
+	return &Collector{
+		failFast: failFast,
+	}
+}
+
+// Add records the error and tells if processing should stop
+func (c *Collector) Add(err error) bool {
+	var diagnostics Diagnostics
+	var positioned *Error
+	if c.first == nil {
+		c.first = err
+		if errors.As(err, &diagnostics) && len(diagnostics) > 0 {
+			c.first = &Error{Pos: diagnostics[0].Pos, Msg: diagnostics[0].Msg}
+		}
+	}
+	switch {
+	case errors.As(err, &diagnostics):
+		c.diagnostics = append(c.diagnostics, diagnostics...)
+	case errors.As(err, &positioned):
+		c.diagnostics = append(c.diagnostics, Diagnostic{SeverityError, positioned.Pos, positioned.Msg})
+	default:
+		c.diagnostics = append(c.diagnostics, Diagnostic{SeverityError, Position{}, err.Error()})
+	}
+	return c.failFast
+}
+
+// Warnf records a warning at the given position
+func (c *Collector) Warnf(pos Position, format string, args ...interface{}) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{SeverityWarning, pos, fmt.Sprintf(format, args...)})
+}
+
+// Stopped tells if processing should not continue, as the first error was found while failing fast
+func (c *Collector) Stopped() bool {
+	return c.failFast && c.first != nil
+}
+
+// Diagnostics returns all recorded diagnostics
+func (c *Collector) Diagnostics() Diagnostics {
+	return c.diagnostics
+}
+
+// Err returns nil if no errors were recorded, the first error when failing fast or all diagnostics otherwise
+func (c *Collector) Err() error {
+	if c.first == nil {
+		return nil
+	}
+	if c.failFast {
+		return c.first
+	}
+	return c.diagnostics
+}
@@ -0,0 +1,83 @@
+package compiler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectorShouldGatherAllErrors(t *testing.T) {
+	t.Parallel()
+
+	// given
+	collector := NewCollector(false)
+	first := Position{Filename: "b.hcl", Line: 2, Column: 3}
+	second := Position{Filename: "a.hcl", Line: 7, Column: 1}
+
+	// when
+	stop := collector.Add(Errorf(first, "first error"))
+	collector.Add(Diagnostics{{SeverityError, second, "second error"}})
+	collector.Add(errors.New("plain error"))
+	collector.Warnf(first, "a warning")
+
+	// then
+	assert.False(t, stop)
+	assert.False(t, collector.Stopped())
+	assert.Equal(t, Diagnostics{
+		{SeverityError, first, "first error"},
+		{SeverityError, second, "second error"},
+		{SeverityError, Position{}, "plain error"},
+		{SeverityWarning, first, "a warning"},
+	}, collector.Err())
+	assert.Equal(t, 3, collector.Diagnostics().Count(SeverityError))
+	assert.Equal(t, 1, collector.Diagnostics().Count(SeverityWarning))
+}
+
+func TestCollectorShouldStopAtFirstErrorWhenFailingFast(t *testing.T) {
+	t.Parallel()
+
+	// given
+	collector := NewCollector(true)
+	pos := Position{Filename: "a.hcl", Line: 2, Column: 3}
+
+	// when
+	stop := collector.Add(Diagnostics{{SeverityError, pos, "first error"}, {SeverityError, pos, "second error"}})
+
+	// then
+	assert.True(t, stop)
+	assert.True(t, collector.Stopped())
+	assert.Equal(t, "a.hcl:2:3: first error", collector.Err().Error())
+}
+
+func TestCollectorShouldNotFailOnWarnings(t *testing.T) {
+	t.Parallel()
+
+	// given
+	collector := NewCollector(false)
+
+	// when
+	collector.Warnf(Position{}, "a warning")
+
+	// then
+	assert.NoError(t, collector.Err())
+	assert.Len(t, collector.Diagnostics(), 1)
+}
+
+func TestShouldSortDiagnosticsByPositions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	diagnostics := Diagnostics{
+		{SeverityError, Position{Filename: "b.hcl", Line: 1, Column: 1}, "b1"},
+		{SeverityError, Position{Filename: "a.hcl", Line: 3, Column: 5}, "a3:5"},
+		{SeverityWarning, Position{Filename: "a.hcl", Line: 3, Column: 2}, "a3:2"},
+		{SeverityError, Position{}, "none"},
+	}
+
+	// when
+	sorted := diagnostics.Sorted()
+
+	// then
+	assert.Equal(t, "none\na.hcl:3:2: warning: a3:2\na.hcl:3:5: a3:5\nb.hcl:1:1: b1", sorted.Error())
+}
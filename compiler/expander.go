@@ -6,17 +6,35 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
+)
+
+const (
+	// maxRangeValues limits values produced by a single range expression
+	maxRangeValues = 10000
+	// maxExpandedHostnames limits hostnames produced by all expanding expressions of a hostname
+	maxExpandedHostnames = 100000
+	// maxRangeWidth limits the explicit width of range values to the maximum length of a DNS label
+	maxRangeWidth = 63
 )
 
 type expander struct {
 	rangeRegexp     *regexp.Regexp
+	rangeItemRegexp *regexp.Regexp
 	variationRegexp *regexp.Regexp
 	hostnameRegexp  *regexp.Regexp
 }
 
+// rangeBound is a decimal number, a hexadecimal number prefixed with `0x` or a single letter
+const rangeBound = `(0x[0-9a-fA-F]+|\d+|[a-zA-Z])`
+
+// rangeItem is a single value or a range `begin..end/step:width`, where step and width are optional
+const rangeItem = rangeBound + `(?:\.\.` + rangeBound + `(?:/(\d+))?(?::(\d+))?)?`
+
 func newExpander() *expander {
 	return &expander{
-		rangeRegexp:     regexp.MustCompile(`\[(\d+)\.\.(\d+)\]`),
+		rangeRegexp:     regexp.MustCompile(`\[!?` + rangeItem + `(?:,!?` + rangeItem + `)*\]`),
+		rangeItemRegexp: regexp.MustCompile(`^` + rangeItem + `$`),
 		variationRegexp: regexp.MustCompile(`\[([a-zA-Z0-9-|]+(?:\.[a-zA-Z0-9-|]+)*)+\]`),
 		hostnameRegexp: regexp.MustCompile(`^([a-zA-Z0-9_]|[a-zA-Z0-9_][a-zA-Z0-9-_]{0,61}[a-zA-Z0-9_])` +
 			`(\.([a-zA-Z0-9_]|[a-zA-Z0-9_][a-zA-Z0-9-_]{0,61}[a-zA-Z0-9_]))*$`),
@@ -52,12 +70,19 @@ func (e *expander) expand(host string) ([]expandedHostname, error) {
 	var ranges = make([]expandingRange, 0, len(e.rangeRegexp.FindAllStringSubmatchIndex(host, -1)))
 	n := 1
 	for _, r := range e.rangeRegexp.FindAllStringSubmatchIndex(host, -1) {
+		if !strings.ContainsAny(host[r[0]:r[1]], ".,!") {
+			// a single value in brackets is a set
+			continue
+		}
 		expRange, err := e.expandingRange(host, r)
 		if err != nil {
 			return nil, err
 		}
 		ranges = append(ranges, expRange)
 		n *= len(expRange.values)
+		if n > maxExpandedHostnames {
+			return nil, tooManyHostnames(host)
+		}
 	}
 	for _, v := range e.variationRegexp.FindAllStringSubmatchIndex(host, -1) {
 		split := strings.Split(host[v[2]:v[3]], "|")
@@ -67,6 +92,9 @@ func (e *expander) expand(host string) ([]expandedHostname, error) {
 			values:   split,
 		})
 		n *= len(split)
+		if n > maxExpandedHostnames {
+			return nil, tooManyHostnames(host)
+		}
 	}
 	if len(ranges) == 0 {
 		if !e.hostnameRegexp.MatchString(host) {
@@ -81,21 +109,19 @@ func (e *expander) expand(host string) ([]expandedHostname, error) {
 	return hostnames, nil
 }
 
+func tooManyHostnames(host string) error {
+	return fmt.Errorf("`%s` expands to more than %d hostnames", host, maxExpandedHostnames)
+}
+
+// expandingRange produces values of a range expression, which is a comma separated list of ranges
+// `begin..end/step:width` and single values. Values of items prefixed with `!` are excluded from the result,
+// remaining values are ordered as declared and duplicates are skipped, so `[1..10,15,!7]` produces
+// `1` ... `6`, `8` ... `10` and `15`.
 func (e *expander) expandingRange(host string, rangeGroup []int) (expandingRange, error) {
-	begin, err := strconv.Atoi(host[rangeGroup[2]:rangeGroup[3]])
-	if err != nil {
-		return expandingRange{}, err
-	}
-	end, err := strconv.Atoi(host[rangeGroup[4]:rangeGroup[5]])
+	expression := host[rangeGroup[0]:rangeGroup[1]]
+	values, err := e.rangeValues(expression)
 	if err != nil {
-		return expandingRange{}, err
-	}
-	if begin >= end {
-		return expandingRange{}, fmt.Errorf("invalid range: %v is not smaller than %v", begin, end)
-	}
-	values := []string{}
-	for i := begin; i <= end; i++ {
-		values = append(values, strconv.Itoa(i))
+		return expandingRange{}, fmt.Errorf("invalid range `%s`: %s", expression, err.Error())
 	}
 	return expandingRange{
 		beginIdx: rangeGroup[0],
@@ -104,6 +130,203 @@ func (e *expander) expandingRange(host string, rangeGroup []int) (expandingRange
 	}, nil
 }
 
+// rangeValue is a single value produced by a range expression, values are compared by their number
+// so an excluded `7` removes `07` produced by a zero-padded range
+type rangeValue struct {
+	number  int64
+	letter  bool
+	printed string
+}
+
+type rangeValueKey struct {
+	number int64
+	letter bool
+}
+
+func (v rangeValue) key() rangeValueKey {
+	return rangeValueKey{number: v.number, letter: v.letter}
+}
+
+func (e *expander) rangeValues(expression string) ([]string, error) {
+	var included []rangeValue
+	excluded := map[rangeValueKey]bool{}
+	var letters bool
+	for i, item := range strings.Split(expression[1:len(expression)-1], ",") {
+		exclusion := strings.HasPrefix(item, "!")
+		values, err := e.rangeItemValues(strings.TrimPrefix(item, "!"))
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			letters = values[0].letter
+		} else if values[0].letter != letters {
+			return nil, fmt.Errorf("numbers and letters cannot be mixed")
+		}
+		for _, v := range values {
+			if exclusion {
+				excluded[v.key()] = true
+			} else {
+				included = append(included, v)
+			}
+		}
+	}
+	values := []string{}
+	produced := map[rangeValueKey]bool{}
+	for _, v := range included {
+		if excluded[v.key()] || produced[v.key()] {
+			continue
+		}
+		produced[v.key()] = true
+		values = append(values, v.printed)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("all values are excluded")
+	}
+	return values, nil
+}
+
+// rangeItemValues produces values of a single value or a range `begin..end/step:width`, where step and width
+// are optional. Ranges with begin greater than end are descending. Numbers are zero-padded to the explicit width,
+// or to the width of bounds when any of them starts with zero, like in `01..12`.
+func (e *expander) rangeItemValues(item string) ([]rangeValue, error) {
+	match := e.rangeItemRegexp.FindStringSubmatch(item)
+	if match[2] == "" {
+		return singleRangeValue(match[1])
+	}
+	bounds, err := parseRangeBounds(match[1], match[2])
+	if err != nil {
+		return nil, err
+	}
+	step := int64(1)
+	if match[3] != "" {
+		step, err = parseRangeNumber(match[3], 10)
+		if err != nil {
+			return nil, err
+		}
+		if step < 1 {
+			return nil, fmt.Errorf("step should be greater than 0")
+		}
+	}
+	width := bounds.width
+	if match[4] != "" {
+		if bounds.letters {
+			return nil, fmt.Errorf("width cannot be applied to letters")
+		}
+		var w int64
+		w, err = parseRangeNumber(match[4], 10)
+		if err != nil {
+			return nil, err
+		}
+		if w > maxRangeWidth {
+			return nil, fmt.Errorf("width should not be greater than %d", maxRangeWidth)
+		}
+		width = int(w)
+	}
+	// bounds are not negative, so the distance between them cannot overflow, unlike stepping past the end
+	distance := bounds.end - bounds.begin
+	if distance < 0 {
+		distance = -distance
+	}
+	steps := distance / step
+	if steps >= maxRangeValues {
+		return nil, fmt.Errorf("range produces more than %d values", maxRangeValues)
+	}
+	if bounds.begin > bounds.end {
+		step = -step
+	}
+	values := make([]rangeValue, 0, steps+1)
+	for k := int64(0); k <= steps; k++ {
+		i := bounds.begin + k*step
+		values = append(values, rangeValue{number: i, letter: bounds.letters, printed: fmt.Sprintf(bounds.format, width, i)})
+	}
+	return values, nil
+}
+
+// singleRangeValue converts a single value of a range expression, which is printed as declared,
+// apart from the `0x` prefix of hexadecimal numbers
+func singleRangeValue(value string) ([]rangeValue, error) {
+	if isLetter(value) {
+		return []rangeValue{{number: int64(value[0]), letter: true, printed: value}}, nil
+	}
+	base := 10
+	if strings.HasPrefix(value, "0x") {
+		base = 16
+		value = value[2:]
+	}
+	n, err := parseRangeNumber(value, base)
+	if err != nil {
+		return nil, err
+	}
+	return []rangeValue{{number: n, printed: value}}, nil
+}
+
+// rangeBounds are bounds of a range converted into numbers, along with the format of values between them
+type rangeBounds struct {
+	begin   int64
+	end     int64
+	format  string
+	width   int
+	letters bool
+}
+
+func parseRangeBounds(beginStr string, endStr string) (rangeBounds, error) {
+	if isLetter(beginStr) || isLetter(endStr) {
+		if !isLetter(beginStr) || !isLetter(endStr) {
+			return rangeBounds{}, fmt.Errorf("both bounds should be either numbers or letters")
+		}
+		if unicode.IsUpper(rune(beginStr[0])) != unicode.IsUpper(rune(endStr[0])) {
+			return rangeBounds{}, fmt.Errorf("both letters should be either lower or upper case")
+		}
+		if beginStr == endStr {
+			return rangeBounds{}, fmt.Errorf("bounds are equal")
+		}
+		return rangeBounds{begin: int64(beginStr[0]), end: int64(endStr[0]), format: "%0*c", letters: true}, nil
+	}
+	bounds := rangeBounds{format: "%0*d"}
+	base := 10
+	if strings.HasPrefix(beginStr, "0x") != strings.HasPrefix(endStr, "0x") {
+		return rangeBounds{}, fmt.Errorf("both bounds should be either decimal or hexadecimal")
+	}
+	if strings.HasPrefix(beginStr, "0x") {
+		base = 16
+		beginStr, endStr = beginStr[2:], endStr[2:]
+		bounds.format = "%0*x"
+		if strings.ContainsAny(beginStr+endStr, "ABCDEF") {
+			bounds.format = "%0*X"
+		}
+	}
+	var err error
+	if bounds.begin, err = parseRangeNumber(beginStr, base); err != nil {
+		return rangeBounds{}, err
+	}
+	if bounds.end, err = parseRangeNumber(endStr, base); err != nil {
+		return rangeBounds{}, err
+	}
+	if bounds.begin == bounds.end {
+		return rangeBounds{}, fmt.Errorf("bounds are equal")
+	}
+	if isZeroPadded(beginStr) || isZeroPadded(endStr) {
+		bounds.width = max(len(beginStr), len(endStr))
+	}
+	return bounds, nil
+}
+
+func isLetter(bound string) bool {
+	return len(bound) == 1 && unicode.IsLetter(rune(bound[0]))
+}
+
+func parseRangeNumber(number string, base int) (int64, error) {
+	n, err := strconv.ParseInt(number, base, 64)
+	if err != nil {
+		return 0, fmt.Errorf("number `%s` is out of range", number)
+	}
+	return n, nil
+}
+
+func isZeroPadded(number string) bool {
+	return len(number) > 1 && strings.HasPrefix(number, "0")
+}
+
 func (e *expander) expandedHostnames(size int, host string, ranges []expandingRange) ([]expandedHostname, error) {
 	var hostnames []expandedHostname
 	sort.Sort(byIndex(ranges))
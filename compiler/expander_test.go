@@ -44,18 +44,151 @@ func TestShouldExpandHostnameWithRange(t *testing.T) {
 	}}, hostnames)
 }
 
+func TestShouldExpandZeroPaddedRanges(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		hostname     string
+		replacements []string
+	}{
+		{"node[01..03]", []string{"01", "02", "03"}},
+		{"node[08..10]", []string{"08", "09", "10"}},
+		{"node[001..3]", []string{"001", "002", "003"}},
+		{"node[8..10:3]", []string{"008", "009", "010"}},
+		{"node[98..100:2]", []string{"98", "99", "100"}},
+		{"node[0..2]", []string{"0", "1", "2"}},
+	}
+
+	for _, e := range entries {
+		// when
+		hostnames, err := newExpander().expand(e.hostname)
+
+		// then
+		assert.NoError(t, err, e.hostname)
+		var replacements []string
+		for _, h := range hostnames {
+			replacements = append(replacements, h.Replacements[0])
+			assert.Equal(t, "node"+h.Replacements[0], h.Hostname)
+		}
+		assert.Equal(t, e.replacements, replacements, e.hostname)
+	}
+}
+
 func TestShouldReturnErrorOnInvalidRange(t *testing.T) {
 	t.Parallel()
 
 	// given
-	hostname := "x-master[120..13].myproj-prod.dc1.net"
+	entries := []struct {
+		hostname string
+		expected string
+	}{
+		{"x-master[13..13].myproj-prod.dc1.net", "invalid range `[13..13]`: bounds are equal"},
+		{"x-master[0..20/0].myproj-prod.dc1.net", "invalid range `[0..20/0]`: step should be greater than 0"},
+		{"shelf[0x0a..15].example.com", "invalid range `[0x0a..15]`: both bounds should be either decimal or hexadecimal"},
+		{"x-master[1..99999999999999999999].myproj-prod.dc1.net", "invalid range `[1..99999999999999999999]`: " +
+			"number `99999999999999999999` is out of range"},
+		{"zone[a..a].example.com", "invalid range `[a..a]`: bounds are equal"},
+		{"zone[a..F].example.com", "invalid range `[a..F]`: both letters should be either lower or upper case"},
+		{"zone[a..5].example.com", "invalid range `[a..5]`: both bounds should be either numbers or letters"},
+		{"zone[a..f:2].example.com", "invalid range `[a..f:2]`: width cannot be applied to letters"},
+		{"node[1..3:64].example.com", "invalid range `[1..3:64]`: width should not be greater than 63"},
+		{"node[1..3:99999999999999999999].example.com", "invalid range `[1..3:99999999999999999999]`: " +
+			"number `99999999999999999999` is out of range"},
+		{"node[1..3,!1..3].example.com", "invalid range `[1..3,!1..3]`: all values are excluded"},
+		{"node[1..3,a].example.com", "invalid range `[1..3,a]`: numbers and letters cannot be mixed"},
+		{"node[1..3,!5..5].example.com", "invalid range `[1..3,!5..5]`: bounds are equal"},
+		{"n[0..10000]", "invalid range `[0..10000]`: range produces more than 10000 values"},
+		{"n[0..9223372036854775807]", "invalid range `[0..9223372036854775807]`: range produces more than 10000 values"},
+		{"n[9223372036854775807..0]", "invalid range `[9223372036854775807..0]`: range produces more than 10000 values"},
+		{"n[1..1000].[1..1000]", "`n[1..1000].[1..1000]` expands to more than 100000 hostnames"},
+	}
+
+	for _, e := range entries {
+		// when
+		_, err := newExpander().expand(e.hostname)
+
+		// then
+		assert.EqualError(t, err, e.expected)
+	}
+}
 
-	// when
-	_, err := newExpander().expand(hostname)
+func TestShouldExpandSteppedDescendingHexadecimalAndAlphabeticRanges(t *testing.T) {
+	t.Parallel()
 
-	// then
-	assert.Error(t, err)
-	assert.Equal(t, "invalid range: 120 is not smaller than 13", err.Error())
+	// given
+	entries := []struct {
+		hostname     string
+		replacements []string
+	}{
+		{"rack[0..8/2]", []string{"0", "2", "4", "6", "8"}},
+		{"rack[1..8/3]", []string{"1", "4", "7"}},
+		{"rack[5..1]", []string{"5", "4", "3", "2", "1"}},
+		{"rack[10..01/4]", []string{"10", "06", "02"}},
+		{"rack[0..20/10:3]", []string{"000", "010", "020"}},
+		{"rack[0x0a..0x0f]", []string{"0a", "0b", "0c", "0d", "0e", "0f"}},
+		{"rack[0xA..0xC]", []string{"A", "B", "C"}},
+		{"rack[0xff..0x100/1]", []string{"ff", "100"}},
+		{"rack[0x10..0x0/8:4]", []string{"0010", "0008", "0000"}},
+		{"rack[a..d]", []string{"a", "b", "c", "d"}},
+		{"rack[C..A]", []string{"C", "B", "A"}},
+		{"rack[a..z/10]", []string{"a", "k", "u"}},
+		{"rack[9223372036854775806..9223372036854775807]", []string{"9223372036854775806", "9223372036854775807"}},
+		{"rack[9223372036854775807..9223372036854775806]", []string{"9223372036854775807", "9223372036854775806"}},
+		{"rack[1..9223372036854775807/4611686018427387904]", []string{"1", "4611686018427387905"}},
+		{"rack[9223372036854775807..0/9223372036854775807]", []string{"9223372036854775807", "0"}},
+		{"rack[0..9999]", nil},
+	}
+
+	for _, e := range entries {
+		// when
+		hostnames, err := newExpander().expand(e.hostname)
+
+		// then
+		assert.NoError(t, err, e.hostname)
+		var replacements []string
+		for _, h := range hostnames {
+			replacements = append(replacements, h.Replacements[0])
+			assert.Equal(t, "rack"+h.Replacements[0], h.Hostname)
+		}
+		if e.replacements == nil {
+			assert.Len(t, replacements, 10000, e.hostname)
+			continue
+		}
+		assert.Equal(t, e.replacements, replacements, e.hostname)
+	}
+}
+
+func TestShouldExpandCompoundRangesWithExclusions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		hostname     string
+		replacements []string
+	}{
+		{"node[1..10,15,20..22,!7]", []string{"1", "2", "3", "4", "5", "6", "8", "9", "10", "15", "20", "21", "22"}},
+		{"node[!2,1..4]", []string{"1", "3", "4"}},
+		{"node[1..6,!2..5/3]", []string{"1", "3", "4", "6"}},
+		{"node[01..12,!7..11]", []string{"01", "02", "03", "04", "05", "06", "12"}},
+		{"node[3,1,2,3]", []string{"3", "1", "2"}},
+		{"node[0x0a..0x0c,!0xb]", []string{"0a", "0c"}},
+		{"node[a..e,!c,x]", []string{"a", "b", "d", "e", "x"}},
+	}
+
+	for _, e := range entries {
+		// when
+		hostnames, err := newExpander().expand(e.hostname)
+
+		// then
+		assert.NoError(t, err, e.hostname)
+		var replacements []string
+		for _, h := range hostnames {
+			replacements = append(replacements, h.Replacements[0])
+			assert.Equal(t, "node"+h.Replacements[0], h.Hostname)
+		}
+		assert.Equal(t, e.replacements, replacements, e.hostname)
+	}
 }
 
 func TestShouldReturnErrorWhenProducedStringIsNotAValidHostname(t *testing.T) {
@@ -234,3 +367,25 @@ func TestShouldExpandHostnameWithRangesAndVariations(t *testing.T) {
 		Replacements: []string{"2", "test", "6"},
 	}}, hostnames)
 }
+
+func TestShouldExpandHostnameWithAlphabeticAndNumericRangesAndVariations(t *testing.T) {
+	t.Parallel()
+
+	// given
+	hostname := "cab[A..B]-[1..2].[eu|us].example.com"
+
+	// when
+	hostnames, err := newExpander().expand(hostname)
+
+	// then
+	assert.NoError(t, err)
+	var produced []string
+	for _, h := range hostnames {
+		produced = append(produced, h.Hostname)
+	}
+	assert.Equal(t, []string{
+		"cabA-1.eu.example.com", "cabB-1.eu.example.com", "cabA-2.eu.example.com", "cabB-2.eu.example.com",
+		"cabA-1.us.example.com", "cabB-1.us.example.com", "cabA-2.us.example.com", "cabB-2.us.example.com",
+	}, produced)
+	assert.Equal(t, []string{"B", "2", "us"}, hostnames[7].Replacements)
+}
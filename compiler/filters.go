@@ -0,0 +1,107 @@
+package compiler
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// filter transforms a value placed into an alias
+type filter func(value string) string
+
+// filterFactories create filters out of arguments following filter names, like `3` in `pad:3`
+var filterFactories = map[string]func(args string) (filter, error){
+	"upper": noArgs(strings.ToUpper),
+	"lower": noArgs(strings.ToLower),
+	"pad": func(args string) (filter, error) {
+		width, err := strconv.Atoi(args)
+		if err != nil || width < 1 {
+			return nil, fmt.Errorf("expects a width greater than 0, got `%s`", args)
+		}
+		return func(value string) string {
+			length := utf8.RuneCountInString(value)
+			if length >= width {
+				return value
+			}
+			return strings.Repeat("0", width-length) + value
+		}, nil
+	},
+	"trim_prefix": func(args string) (filter, error) {
+		if args == "" {
+			return nil, fmt.Errorf("expects a prefix")
+		}
+		return func(value string) string {
+			return strings.TrimPrefix(value, args)
+		}, nil
+	},
+	"trim_suffix": func(args string) (filter, error) {
+		if args == "" {
+			return nil, fmt.Errorf("expects a suffix")
+		}
+		return func(value string) string {
+			return strings.TrimSuffix(value, args)
+		}, nil
+	},
+	"first": func(args string) (filter, error) {
+		n, err := strconv.Atoi(args)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("expects a number of characters greater than 0, got `%s`", args)
+		}
+		return func(value string) string {
+			if runes := []rune(value); len(runes) > n {
+				return string(runes[:n])
+			}
+			return value
+		}, nil
+	},
+	"replace": func(args string) (filter, error) {
+		replaced, replacement, ok := strings.Cut(args, ":")
+		if !ok || replaced == "" {
+			return nil, fmt.Errorf("expects a replaced string and a replacement separated with `:`, got `%s`", args)
+		}
+		return func(value string) string {
+			return strings.ReplaceAll(value, replaced, replacement)
+		}, nil
+	},
+}
+
+func noArgs(f filter) func(args string) (filter, error) {
+	return func(args string) (filter, error) {
+		if args != "" {
+			return nil, fmt.Errorf("does not take arguments, got `%s`", args)
+		}
+		return f, nil
+	}
+}
+
+// parseFilters converts a pipeline like `|trim_prefix:web-|upper` into filters applied in the declared order
+func parseFilters(pipeline string) ([]filter, error) {
+	if pipeline == "" {
+		return nil, nil
+	}
+	var filters []filter
+	for _, f := range strings.Split(pipeline[1:], "|") {
+		name, args, _ := strings.Cut(f, ":")
+		factory, ok := filterFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter `%s`, expected one of `%s`", name, strings.Join(filterNames(), "`, `"))
+		}
+		created, err := factory(args)
+		if err != nil {
+			return nil, fmt.Errorf("filter `%s` %s", name, err.Error())
+		}
+		filters = append(filters, created)
+	}
+	return filters, nil
+}
+
+func filterNames() []string {
+	names := make([]string, 0, len(filterFactories))
+	for name := range filterFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,77 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldApplyFiltersInDeclaredOrder(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		pipeline string
+		value    string
+		expected string
+	}{
+		{"", "web", "web"},
+		{"|upper", "web", "WEB"},
+		{"|lower", "WEB", "web"},
+		{"|pad:3", "7", "007"},
+		{"|pad:2", "123", "123"},
+		{"|pad:4", "ab", "00ab"},
+		{"|pad:4", "żó", "00żó"},
+		{"|pad:2", "żó", "żó"},
+		{"|first:1|pad:3", "źródło", "00ź"},
+		{"|trim_prefix:my-service-", "my-service-api", "api"},
+		{"|trim_suffix:.example.com", "api.example.com", "api"},
+		{"|first:1", "prod", "p"},
+		{"|first:10", "prod", "prod"},
+		{"|replace:.:-", "10.0.0.1", "10-0-0-1"},
+		{"|replace:-:", "a-b-c", "abc"},
+		{"|trim_prefix:my-|first:3|upper", "my-service", "SER"},
+	}
+
+	for _, e := range entries {
+		// when
+		filters, err := parseFilters(e.pipeline)
+
+		// then
+		assert.NoError(t, err, e.pipeline)
+		value := e.value
+		for _, f := range filters {
+			value = f(value)
+		}
+		assert.Equal(t, e.expected, value, e.pipeline)
+	}
+}
+
+func TestShouldReturnErrorOnInvalidFilters(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		pipeline string
+		expected string
+	}{
+		{"|capitalize", "unknown filter `capitalize`, expected one of " +
+			"`first`, `lower`, `pad`, `replace`, `trim_prefix`, `trim_suffix`, `upper`"},
+		{"|", "unknown filter ``, expected one of " +
+			"`first`, `lower`, `pad`, `replace`, `trim_prefix`, `trim_suffix`, `upper`"},
+		{"|upper:1", "filter `upper` does not take arguments, got `1`"},
+		{"|pad", "filter `pad` expects a width greater than 0, got ``"},
+		{"|pad:x", "filter `pad` expects a width greater than 0, got `x`"},
+		{"|first:0", "filter `first` expects a number of characters greater than 0, got `0`"},
+		{"|trim_prefix", "filter `trim_prefix` expects a prefix"},
+		{"|replace:.", "filter `replace` expects a replaced string and a replacement separated with `:`, got `.`"},
+	}
+
+	for _, e := range entries {
+		// when
+		_, err := parseFilters(e.pipeline)
+
+		// then
+		assert.EqualError(t, err, e.expected, e.pipeline)
+	}
+}
@@ -0,0 +1,40 @@
+package compiler
+
+import "fmt"
+
+// Position points a location in a source file, lines and columns start at 1
+type Position struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+// IsValid tells if the position points a specific line of the source file
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return p.Filename
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// Error is an error caused by a definition declared at the given position
+type Error struct {
+	Pos Position
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if pos := e.Pos.String(); pos != "" {
+		return pos + ": " + e.Msg
+	}
+	return e.Msg
+}
+
+// Errorf creates an Error at the given position
+func Errorf(pos Position, format string, args ...interface{}) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
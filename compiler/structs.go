@@ -10,6 +10,12 @@ type ExpandingHostConfig struct {
 	HostnamePattern string
 	AliasTemplate   string
 	Config          ConfigProperties
+	// Priority orders compiled Host sections, sections of definitions with higher priority come first
+	Priority int
+	// Position points the host definition, HostnamePosition and AliasPosition point its hostname and alias values
+	Position         Position
+	HostnamePosition Position
+	AliasPosition    Position
 }
 
 // IsRegexpHostDefinition checks if the specified host definition should be compiled
@@ -30,6 +36,26 @@ type InputContext struct {
 type ContextSource struct {
 	SourceName string
 	Hosts      []ExpandingHostConfig
+	Matches    []MatchConfig
+}
+
+// MatchConfig is a definition of ssh config Match section, that applies config properties
+// to connections meeting all of its criteria
+type MatchConfig struct {
+	Name     string
+	Criteria []MatchCriterion
+	Config   ConfigProperties
+	// Top places the section before all Host sections, otherwise it follows them
+	Top bool
+	// Position points the match definition
+	Position Position
+}
+
+// MatchCriterion is a single criterion of Match section, value is empty for criteria without arguments
+// like `canonical` or `all`
+type MatchCriterion struct {
+	Keyword string
+	Value   string
 }
 
 // HostEntity is the outcome of ssh-alises compiler
@@ -11,139 +11,215 @@ import (
 
 type rawContextSource struct {
 	SourceName string
+	Layer      int
 	RawContext rawFileContext
 }
 
-func compilerInputContext(sources []rawContextSource) (compiler.InputContext, error) {
-	err := validateHosts(sources)
-	if err != nil {
-		return compiler.InputContext{}, err
+// compilerInputContext converts sources into compiler inputs, definitions with errors are reported to diagnostics and skipped.
+// Config properties are sorted alphabetically, unless keepPropertyOrder is set.
+func compilerInputContext(sources []rawContextSource, keepPropertyOrder bool,
+	diagnostics *compiler.Collector) compiler.InputContext {
+	sources = validHosts(sources, diagnostics)
+	if diagnostics.Stopped() {
+		return compiler.InputContext{}
 	}
-	variables, err := normalizedVariables(sources)
-	if err != nil {
-		return compiler.InputContext{}, err
+	variables := normalizedVariables(sources, diagnostics)
+	if diagnostics.Stopped() {
+		return compiler.InputContext{}
 	}
-	namedProps, err := getNamedConfigProps(sources, variables)
-	if err != nil {
-		return compiler.InputContext{}, err
+	namedProps := getNamedConfigProps(sources, variables, diagnostics)
+	if diagnostics.Stopped() {
+		return compiler.InputContext{}
 	}
 	var ctxSources = make([]compiler.ContextSource, 0, len(sources))
 	for _, s := range sources {
-		expandingHostConfigs, err := expandingHostConfigs(s.RawContext, variables, namedProps)
-		if err != nil {
-			return compiler.InputContext{}, fmt.Errorf("error in `%s`: %s", s.SourceName, err.Error())
+		expandingHostConfigs := expandingHostConfigs(s.RawContext, variables, namedProps, diagnostics)
+		if diagnostics.Stopped() {
+			return compiler.InputContext{}
+		}
+		matches := matchConfigs(s.RawContext, variables, namedProps, diagnostics)
+		if diagnostics.Stopped() {
+			return compiler.InputContext{}
 		}
 		ctxSources = append(ctxSources, compiler.ContextSource{
 			SourceName: s.SourceName,
 			Hosts:      expandingHostConfigs,
+			Matches:    matches,
 		})
 	}
+	if !keepPropertyOrder {
+		sortProperties(ctxSources)
+	}
 	return compiler.InputContext{
 		Sources: ctxSources,
-	}, nil
+	}
 }
 
-func validateHosts(sources []rawContextSource) error {
-	hosts := make(map[string]struct{})
-	var exists struct{}
+// validHosts skips host definitions without both alias and hostname
+func validHosts(sources []rawContextSource, diagnostics *compiler.Collector) []rawContextSource {
+	valid := make([]rawContextSource, 0, len(sources))
 	for _, s := range sources {
+		hosts := make([]host, 0, len(s.RawContext.Hosts))
 		for _, h := range s.RawContext.Hosts {
 			if strings.TrimSpace(h.Alias) == "" && strings.TrimSpace(h.Hostname) == "" {
-				return fmt.Errorf("error in `%s`: invalid `%s` host definition: alias and hostname are both empty or undefined",
-					s.SourceName, h.Name)
-			}
-			if _, contains := hosts[h.Name]; contains {
-				return fmt.Errorf("duplicate host `%v`", h.Name)
+				err := compiler.Errorf(s.RawContext.positions.of("host", h.Name),
+					"invalid `%s` host definition: alias and hostname are both empty or undefined", h.Name)
+				if diagnostics.Add(err) {
+					return nil
+				}
+				continue
 			}
-			hosts[h.Name] = exists
+			hosts = append(hosts, h)
 		}
+		s.RawContext.Hosts = hosts
+		valid = append(valid, s)
 	}
-	return nil
+	return valid
 }
 
-func getNamedConfigProps(sources []rawContextSource, variables variablesMap) (map[string]configProps, error) {
-	configToSourceMap := map[string]string{}
+// getNamedConfigProps evaluates all named configs, configs with errors are evaluated as far as possible,
+// so hosts referring them do not report errors again
+func getNamedConfigProps(sources []rawContextSource, variables variablesMap,
+	diagnostics *compiler.Collector) map[string]configProps {
+	configToPositionsMap := map[string]sourcePositions{}
 	propsMap := map[string]configProps{}
 	for _, s := range sources {
-		for name, r := range s.RawContext.RawConfigs {
-			configToSourceMap[name] = s.SourceName
-			interpolated, err := interpolatedConfigProps(variables, r)
-			if err != nil {
-				return nil, fmt.Errorf("error in `%s`: invalid `%s` config definition: %s",
-					configToSourceMap[name], name, err.Error())
+		positions := s.RawContext.positions
+		for _, name := range sortedKeys(s.RawContext.RawConfigs) {
+			configToPositionsMap[name] = positions
+			interpolated, err := interpolatedConfigProps(variables, s.RawContext.RawConfigs[name], positions, "config", name)
+			if err != nil && diagnostics.Add(wrapError(err, positions.of("config", name), "invalid `%s` config definition", name)) {
+				return nil
 			}
 			propsMap[name] = interpolated
 		}
 	}
 	evaluated := map[string]configProps{}
-	for name, props := range propsMap {
+	for _, name := range sortedPropsKeys(propsMap) {
+		props := propsMap[name]
 		evaluatedImports := make([]string, 0)
 		evaluatedConfig, err := props.evaluateConfigImports(propsMap, &evaluatedImports)
 		if err != nil {
-			return nil, fmt.Errorf("error in `%s`: invalid `%s` config definition: %s", configToSourceMap[name], name, err.Error())
+			err = wrapError(err, configToPositionsMap[name].of("config", name, extendConfigKey),
+				"invalid `%s` config definition", name)
+			if diagnostics.Add(err) {
+				return nil
+			}
+			evaluatedConfig = props.withoutImports()
 		}
 		evaluated[name] = evaluatedConfig
 	}
-	return evaluated, nil
+	return evaluated
 }
 
-func expandingHostConfigs(fileCtx rawFileContext, variables variablesMap, propsMap map[string]configProps) ([]compiler.ExpandingHostConfig, error) {
-	configsMap := propsMap
+func expandingHostConfigs(fileCtx rawFileContext, variables variablesMap, propsMap map[string]configProps,
+	diagnostics *compiler.Collector) []compiler.ExpandingHostConfig {
 	inputs := []compiler.ExpandingHostConfig{}
-
 	for _, a := range fileCtx.Hosts {
-		config := compiler.ConfigProperties{}
-
-		switch v := a.RawConfigOrRef.(type) {
-		case string:
-			if named, ok := configsMap[v]; ok {
-				config = sortedCompilerProperties(named)
-			} else {
-				return nil, fmt.Errorf("error in `%s` host definition: no config `%s` found",
-					a.Name, v)
-			}
-		case []map[string]interface{}:
-			interpolated, err := interpolatedConfigProps(variables, v)
-			if err != nil {
-				return nil, fmt.Errorf("error in `%s` host definition: %s", a.Name, err.Error())
-			}
-			evaluatedImports := make([]string, 0)
-			evaluated, err := interpolated.evaluateConfigImports(configsMap, &evaluatedImports)
-			if err != nil {
-				return nil, fmt.Errorf("error in `%s` host definition: %s", a.Name, err.Error())
-			}
-			config = sortedCompilerProperties(evaluated)
-		case nil:
-			if strings.TrimSpace(a.Hostname) == "" {
-				return nil, fmt.Errorf("no config nor hostname specified for host `%v`", a.Name)
+		input, err := expandingHostConfig(a, fileCtx.positions, variables, propsMap)
+		if err != nil {
+			if diagnostics.Add(err) {
+				return nil
 			}
-		default:
-			return nil, fmt.Errorf("invalid config definition for host `%v`", a.Name)
+			continue
 		}
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+func expandingHostConfig(a host, positions sourcePositions, variables variablesMap,
+	configsMap map[string]configProps) (compiler.ExpandingHostConfig, error) {
+	config, err := definitionConfig("host", a.Name, a.RawConfigOrRef, positions, variables, configsMap)
+	if err != nil {
+		return compiler.ExpandingHostConfig{}, err
+	}
+	if config == nil && strings.TrimSpace(a.Hostname) == "" {
+		return compiler.ExpandingHostConfig{}, compiler.Errorf(positions.of("host", a.Name),
+			"no config nor hostname specified for host `%v`", a.Name)
+	}
 
-		interpolatedHostname, err := applyVariablesToString(a.Hostname, variables)
+	var errs compiler.Diagnostics
+	hostnamePos := positions.of("host", a.Name, "hostname")
+	interpolatedHostname, err := applyVariablesToString(a.Hostname, variables)
+	if err != nil {
+		errs = append(errs, compiler.Diagnostic{Pos: hostnamePos,
+			Msg: fmt.Sprintf("error in hostname of `%s` host definition: %s", a.Name, err.Error())})
+	}
+	aliasPos := positions.of("host", a.Name, "alias")
+	interpolatedAlias, err := applyVariablesToString(a.Alias, variables)
+	if err != nil {
+		errs = append(errs, compiler.Diagnostic{Pos: aliasPos,
+			Msg: fmt.Sprintf("error in alias of `%s` host definition: %s", a.Name, err.Error())})
+	}
+	if len(errs) > 0 {
+		return compiler.ExpandingHostConfig{}, errs
+	}
+	return compiler.ExpandingHostConfig{
+		AliasName:        a.Name,
+		HostnamePattern:  interpolatedHostname,
+		AliasTemplate:    interpolatedAlias,
+		Config:           configOrEmpty(config),
+		Priority:         a.Priority,
+		Position:         positions.of("host", a.Name),
+		HostnamePosition: hostnamePos,
+		AliasPosition:    aliasPos,
+	}, nil
+}
+
+// definitionConfig evaluates config of a host or match definition, which is either a name of config definition
+// or config properties, possibly extending other configs. Nil is returned for definitions without config.
+func definitionConfig(kind string, name string, rawConfigOrRef interface{}, positions sourcePositions,
+	variables variablesMap, configsMap map[string]configProps) (compiler.ConfigProperties, error) {
+	configPos := positions.of(kind, name, "config")
+	switch v := rawConfigOrRef.(type) {
+	case string:
+		if named, ok := configsMap[v]; ok {
+			return compilerProperties(named), nil
+		}
+		return nil, compiler.Errorf(configPos, "error in `%s` %s definition: no config `%s` found", name, kind, v)
+	case []map[string]interface{}:
+		interpolated, err := interpolatedConfigProps(variables, v, positions, kind, name, "config")
 		if err != nil {
-			return nil, fmt.Errorf("error in hostname of `%s` host definition: %s", a.Name, err.Error())
+			return nil, wrapError(err, configPos, "error in `%s` %s definition", name, kind)
 		}
-		interpolatedAlias, err := applyVariablesToString(a.Alias, variables)
+		evaluatedImports := make([]string, 0)
+		evaluated, err := interpolated.evaluateConfigImports(configsMap, &evaluatedImports)
 		if err != nil {
-			return nil, fmt.Errorf("error in alias of `%s` host definition: %s", a.Name, err.Error())
+			return nil, wrapError(err, positions.of(kind, name, "config", extendConfigKey),
+				"error in `%s` %s definition", name, kind)
 		}
-		inputs = append(inputs, compiler.ExpandingHostConfig{
-			AliasName:       a.Name,
-			HostnamePattern: interpolatedHostname,
-			AliasTemplate:   interpolatedAlias,
-			Config:          config,
-		})
+		return compilerProperties(evaluated), nil
+	case nil:
+		return nil, nil
 	}
-	return inputs, nil
+	return nil, compiler.Errorf(configPos, "invalid config definition for %s `%v`", kind, name)
 }
 
-func sortedCompilerProperties(props configProps) compiler.ConfigProperties {
-	var entries = make([]compiler.ConfigProperty, 0, len(props))
-	for k, v := range props {
-		entries = append(entries, compiler.ConfigProperty{Key: sanitize(k), Value: v})
+func configOrEmpty(config compiler.ConfigProperties) compiler.ConfigProperties {
+	if config == nil {
+		return compiler.ConfigProperties{}
+	}
+	return config
+}
+
+// compilerProperties converts props into compiler properties, in order of their declaration
+func compilerProperties(props configProps) compiler.ConfigProperties {
+	var entries = make([]compiler.ConfigProperty, 0, len(props.keys))
+	for _, k := range props.keys {
+		entries = append(entries, compiler.ConfigProperty{Key: sanitize(k), Value: props.values[k]})
 	}
-	sort.Sort(compiler.ByConfigPropertyKey(entries))
 	return entries
 }
+
+// sortProperties sorts properties of all definitions alphabetically by their keys
+func sortProperties(sources []compiler.ContextSource) {
+	for _, s := range sources {
+		for _, h := range s.Hosts {
+			sort.Sort(compiler.ByConfigPropertyKey(h.Config))
+		}
+		for _, m := range s.Matches {
+			sort.Sort(compiler.ByConfigPropertyKey(m.Config))
+		}
+	}
+}
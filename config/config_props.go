@@ -3,31 +3,78 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/dankraw/ssh-aliases/compiler"
 )
 
-type configProps map[string]interface{}
+// configProps are properties of a config definition, along with the order of their declaration
+type configProps struct {
+	values map[string]interface{}
+	// keys lists property keys in order of their declaration, followed by keys of extended configs
+	keys []string
+}
+
+func newConfigProps() configProps {
+	return configProps{values: map[string]interface{}{}}
+}
+
+// set replaces the value of a property, a new property is appended to the declared ones
+func (c *configProps) set(key string, value interface{}) {
+	if _, ok := c.values[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+	c.values[key] = value
+}
 
 type variablesMap map[string]string
 
-func interpolatedConfigProps(variables variablesMap, rawConfig []map[string]interface{}) (configProps, error) {
-	h := configProps{}
+// interpolatedConfigProps applies variables to config properties declared at the given path,
+// properties that cannot be interpolated are reported together and keep their raw values
+func interpolatedConfigProps(variables variablesMap, rawConfig []map[string]interface{},
+	positions sourcePositions, path ...string) (configProps, error) {
+	raw := map[string]interface{}{}
 	for _, x := range rawConfig {
 		for k, v := range x {
-			if vStr, ok := v.(string); ok {
-				interpolated, err := applyVariablesToString(vStr, variables)
-				if err != nil {
-					return nil, fmt.Errorf("could not compile config property `%s`: %s", k, err.Error())
-				}
-				h[k] = interpolated
-			} else {
-				h[k] = v
-			}
+			raw[k] = v
 		}
 	}
+	h := newConfigProps()
+	var errs compiler.Diagnostics
+	for _, k := range declaredPropertyKeys(raw, positions, path) {
+		v := raw[k]
+		value, err := propertyValue(k, v, variables)
+		if err != nil {
+			errs = append(errs, compiler.Diagnostic{
+				Pos: positions.of(append(path, k)...),
+				Msg: fmt.Sprintf("could not compile config property `%s`: %s", k, err.Error()),
+			})
+			value = v
+		}
+		h.set(k, value)
+	}
+	if len(errs) > 0 {
+		return h, errs
+	}
 	return h, nil
 }
 
+// declaredPropertyKeys orders keys by positions of their declarations, keys without known positions
+// are ordered alphabetically
+func declaredPropertyKeys(props map[string]interface{}, positions sourcePositions, path []string) []string {
+	keys := sortedPropertyKeys(props)
+	sort.SliceStable(keys, func(i, j int) bool {
+		a := positions.of(append(path, keys[i])...)
+		b := positions.of(append(path, keys[j])...)
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return keys
+}
+
 var variableRegexp = regexp.MustCompile(`\${([^}]+)}`)
 
 func applyVariablesToString(str string, vals variablesMap) (string, error) {
@@ -51,58 +98,74 @@ func applyVariablesToString(str string, vals variablesMap) (string, error) {
 const extendConfigKey = "_extend"
 
 func (c configProps) evaluateConfigImports(propsMap map[string]configProps, evaluatedImports *[]string) (configProps, error) {
-	if value, ok := c[extendConfigKey]; ok {
-		evaluated := configProps{}
-		if importedStr, ok := value.(string); ok {
-			imported, err := importProps(importedStr, propsMap, evaluatedImports)
-			if err != nil {
-				return nil, err
-			}
-			for k, v := range imported {
-				evaluated[k] = v
-			}
-		} else if importedArr, ok := value.([]interface{}); ok {
-			for _, importedInterface := range importedArr {
-				if importedStr, ok := importedInterface.(string); ok {
-
-					// each import branch needs a copy of evaluated imports list
-					evaluatedImportsBranch := make([]string, len(*evaluatedImports))
-					copy(evaluatedImportsBranch, *evaluatedImports)
-
-					imported, err := importProps(importedStr, propsMap, &evaluatedImportsBranch)
-					if err != nil {
-						return nil, err
-					}
-					for k, v := range imported {
-						evaluated[k] = v
-					}
-				} else {
-					return nil, fmt.Errorf("config import statement has invalid value: `%v`", importedInterface)
+	value, ok := c.values[extendConfigKey]
+	if !ok {
+		return c, nil
+	}
+	imported := newConfigProps()
+	if importedStr, ok := value.(string); ok {
+		props, err := importProps(importedStr, propsMap, evaluatedImports)
+		if err != nil {
+			return configProps{}, err
+		}
+		imported.extend(props)
+	} else if importedArr, ok := value.([]interface{}); ok {
+		for _, importedInterface := range importedArr {
+			if importedStr, ok := importedInterface.(string); ok {
+
+				// each import branch needs a copy of evaluated imports list
+				evaluatedImportsBranch := make([]string, len(*evaluatedImports))
+				copy(evaluatedImportsBranch, *evaluatedImports)
+
+				props, err := importProps(importedStr, propsMap, &evaluatedImportsBranch)
+				if err != nil {
+					return configProps{}, err
 				}
+				imported.extend(props)
+			} else {
+				return configProps{}, fmt.Errorf("config import statement has invalid value: `%v`", importedInterface)
 			}
-		} else {
-			return nil, fmt.Errorf("config import statement has invalid value: `%v`", value)
 		}
-		for key, value := range c {
-			if key != extendConfigKey {
-				evaluated[key] = value
-			}
+	} else {
+		return configProps{}, fmt.Errorf("config import statement has invalid value: `%v`", value)
+	}
+	evaluated := c.withoutImports()
+	for _, k := range imported.keys {
+		if _, ok := evaluated.values[k]; !ok {
+			evaluated.set(k, imported.values[k])
+		}
+	}
+	return evaluated, nil
+}
+
+// extend overrides values of properties with the ones from provided props
+func (c *configProps) extend(props configProps) {
+	for _, k := range props.keys {
+		c.set(k, props.values[k])
+	}
+}
+
+// withoutImports returns properties declared directly in config, ignoring the configs it extends
+func (c configProps) withoutImports() configProps {
+	props := newConfigProps()
+	for _, k := range c.keys {
+		if k != extendConfigKey {
+			props.set(k, c.values[k])
 		}
-		return evaluated, nil
 	}
-	return c, nil
+	return props
 }
 
 func importProps(importedStr string, propsMap map[string]configProps, evaluatedImports *[]string) (configProps, error) {
 	if contains(*evaluatedImports, importedStr) {
-		return nil, fmt.Errorf("circular import in configs (config imports chain: `%s` -> `%s`)",
+		return configProps{}, fmt.Errorf("circular import in configs (config imports chain: `%s` -> `%s`)",
 			strings.Join(*evaluatedImports, " -> "), importedStr)
 	}
 	*evaluatedImports = append(*evaluatedImports, importedStr)
 	if imported, ok := propsMap[importedStr]; ok {
 		return imported.evaluateConfigImports(propsMap, evaluatedImports)
 	}
-	return nil, fmt.Errorf("trying to import `%s`, but such config does not exist", importedStr)
+	return configProps{}, fmt.Errorf("trying to import `%s`, but such config does not exist", importedStr)
 }
 
 func contains(slice []string, element string) bool {
@@ -113,3 +176,21 @@ func contains(slice []string, element string) bool {
 	}
 	return false
 }
+
+func sortedPropertyKeys(props map[string]interface{}) []string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPropsKeys(propsMap map[string]configProps) []string {
+	keys := make([]string, 0, len(propsMap))
+	for k := range propsMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
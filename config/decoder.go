@@ -1,24 +1,123 @@
 package config
 
 import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dankraw/ssh-aliases/compiler"
 	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+	"github.com/hashicorp/hcl/hcl/token"
 )
 
-type decoder struct{}
+// decodeFunc converts contents of a single file into rawFileContext
+type decodeFunc func(fileName string, input []byte) (rawFileContext, error)
+
+// decoder selects a decodeFunc by extension of decoded file
+type decoder struct {
+	formats map[string]decodeFunc
+}
 
 func newDecoder() *decoder {
-	return &decoder{}
+	d := &decoder{
+		formats: map[string]decodeFunc{},
+	}
+	d.register(decodeHCL, hclExtension)
+	d.register(decodeHCL2, hcl2Extension)
+	d.register(decodeYAML, ".yaml", ".yml")
+	d.register(decodeJSON, ".json")
+	d.register(decodeTOML, ".toml")
+	return d
+}
+
+func (d *decoder) register(decode decodeFunc, extensions ...string) {
+	for _, e := range extensions {
+		d.formats[e] = decode
+	}
+}
+
+func (d *decoder) supports(fileName string) bool {
+	_, ok := d.formats[filepath.Ext(fileName)]
+	return ok
+}
+
+func (d *decoder) decode(fileName string, input []byte) (rawFileContext, error) {
+	decode, ok := d.formats[filepath.Ext(fileName)]
+	if !ok {
+		return rawFileContext{}, fmt.Errorf("unsupported file extension `%s`", filepath.Ext(fileName))
+	}
+	c, err := decode(fileName, input)
+	if err != nil {
+		return rawFileContext{}, err
+	}
+	if c.positions.paths == nil {
+		c.positions = newSourcePositions(fileName)
+	}
+	return c, nil
+}
+
+// decodeHCL reads files as HCL1, unless they turn out to be valid HCL2 files only.
+// When both fail, errors of HCL2 are reported for files written in HCL2 only syntax.
+func decodeHCL(fileName string, input []byte) (rawFileContext, error) {
+	config, err := decodeHCL1(fileName, input)
+	if err != nil {
+		detected, hcl2Err := decodeHCL2(fileName, input)
+		if hcl2Err == nil {
+			return detected, nil
+		}
+		if _, hcl1Err := hcl.ParseBytes(input); hcl1Err != nil && hcl2Syntax(fileName, input) {
+			return rawFileContext{}, hcl2Err
+		}
+		return rawFileContext{}, err
+	}
+	return config, nil
 }
 
-func (d *decoder) decode(input []byte) (rawFileContext, error) {
+func decodeHCL1(fileName string, input []byte) (rawFileContext, error) {
 	config := rawFileContext{}
 	file, err := hcl.ParseBytes(input)
 	if err != nil {
+		if posErr, ok := err.(*parser.PosError); ok {
+			return rawFileContext{}, &compiler.Error{Pos: hcl1Position(fileName, posErr.Pos), Msg: posErr.Err.Error()}
+		}
 		return rawFileContext{}, err
 	}
 	err = hcl.DecodeObject(&config, file)
 	if err != nil {
 		return rawFileContext{}, err
 	}
+	config.positions = newSourcePositions(fileName)
+	if list, ok := file.Node.(*ast.ObjectList); ok {
+		addHCL1Positions(config.positions, fileName, list, nil)
+	}
 	return config, nil
 }
+
+// addHCL1Positions records positions of blocks and of values of attributes
+func addHCL1Positions(positions sourcePositions, fileName string, list *ast.ObjectList, prefix []string) {
+	for _, item := range list.Items {
+		path := append([]string{}, prefix...)
+		for _, k := range item.Keys {
+			path = append(path, fmt.Sprintf("%v", k.Token.Value()))
+		}
+		switch v := item.Val.(type) {
+		case *ast.ObjectType:
+			positions.add(hcl1Position(fileName, item.Keys[0].Pos()), path...)
+			addHCL1Positions(positions, fileName, v.List, path)
+		case *ast.ListType:
+			positions.add(hcl1Position(fileName, v.Pos()), path...)
+			for _, element := range v.List {
+				if object, ok := element.(*ast.ObjectType); ok {
+					addHCL1Positions(positions, fileName, object.List, path)
+				}
+			}
+		default:
+			positions.add(hcl1Position(fileName, v.Pos()), path...)
+		}
+	}
+}
+
+func hcl1Position(fileName string, pos token.Pos) compiler.Position {
+	return compiler.Position{Filename: fileName, Line: pos.Line, Column: pos.Column}
+}
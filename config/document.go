@@ -0,0 +1,241 @@
+package config
+
+import "github.com/dankraw/ssh-aliases/compiler"
+
+// document is an ordered object decoded from data formats like YAML, JSON or TOML,
+// values are strings, numbers (int or float64), booleans, nils, []interface{} or nested documents
+type document []documentEntry
+
+// documentEntry points the value for scalars, or the key for nested documents and lists,
+// positions of formats that do not provide them are not valid
+type documentEntry struct {
+	key   string
+	value interface{}
+	pos   compiler.Position
+}
+
+// documentContext maps a document onto rawFileContext, the same way HCL decoder does:
+//
+//	host:
+//	  <name>: {hostname: ..., alias: ..., config: <name or properties>}
+//	match:
+//	  <name>: {host: ..., user: ..., exec: ..., position: ..., config: <name or properties>}
+//	config:
+//	  <name>: {<properties>}
+//	var:
+//	  <variables>
+func documentContext(fileName string, doc document) (rawFileContext, error) {
+	config := rawFileContext{
+		positions: newSourcePositions(fileName),
+	}
+	addDocumentPositions(config.positions, doc, nil)
+	for _, e := range doc {
+		switch e.key {
+		case "host":
+			hosts, err := documentValue(e.value, e.key, e.pos)
+			if err != nil {
+				return rawFileContext{}, err
+			}
+			for _, h := range hosts {
+				decoded, err := documentHost(h)
+				if err != nil {
+					return rawFileContext{}, err
+				}
+				config.Hosts = append(config.Hosts, decoded)
+			}
+		case "match":
+			matches, err := documentValue(e.value, e.key, e.pos)
+			if err != nil {
+				return rawFileContext{}, err
+			}
+			for _, m := range matches {
+				decoded, err := documentMatch(m)
+				if err != nil {
+					return rawFileContext{}, err
+				}
+				config.Matches = append(config.Matches, decoded)
+			}
+		case "config":
+			configs, err := documentValue(e.value, e.key, e.pos)
+			if err != nil {
+				return rawFileContext{}, err
+			}
+			config.RawConfigs = map[string]rawConfig{}
+			for _, c := range configs {
+				props, err := documentValue(c.value, "config."+c.key, c.pos)
+				if err != nil {
+					return rawFileContext{}, err
+				}
+				config.RawConfigs[c.key] = rawConfig{props.values()}
+			}
+		case "var":
+			vars, err := documentValue(e.value, e.key, e.pos)
+			if err != nil {
+				return rawFileContext{}, err
+			}
+			config.Variables = vars.values()
+		default:
+			return rawFileContext{}, compiler.Errorf(e.pos, "unsupported key `%s`, expected `host`, `match`, `config` or `var`", e.key)
+		}
+	}
+	return config, nil
+}
+
+func documentHost(entry documentEntry) (host, error) {
+	path := "host." + entry.key
+	doc, err := documentValue(entry.value, path, entry.pos)
+	if err != nil {
+		return host{}, err
+	}
+	h := host{Name: entry.key}
+	for _, e := range doc {
+		switch e.key {
+		case "hostname":
+			h.Hostname, err = documentString(e.value, path+".hostname", e.pos)
+		case "alias":
+			h.Alias, err = documentString(e.value, path+".alias", e.pos)
+		case "priority":
+			h.Priority, err = documentInt(e.value, path+".priority", e.pos)
+		case "config":
+			h.RawConfigOrRef = normalizedDocumentValue(e.value)
+			if _, ok := h.RawConfigOrRef.([]interface{}); ok {
+				err = compiler.Errorf(e.pos, "invalid `%s.config`: expected a config name or properties", path)
+			}
+		default:
+			err = compiler.Errorf(e.pos, "unsupported key `%s.%s`, expected `hostname`, `alias`, `priority` or `config`", path, e.key)
+		}
+		if err != nil {
+			return host{}, err
+		}
+	}
+	return h, nil
+}
+
+func documentMatch(entry documentEntry) (match, error) {
+	path := "match." + entry.key
+	doc, err := documentValue(entry.value, path, entry.pos)
+	if err != nil {
+		return match{}, err
+	}
+	m := match{Name: entry.key}
+	for _, e := range doc {
+		switch e.key {
+		case "host":
+			m.Host = normalizedDocumentValue(e.value)
+		case "originalhost":
+			m.OriginalHost = normalizedDocumentValue(e.value)
+		case "user":
+			m.User = normalizedDocumentValue(e.value)
+		case "localuser":
+			m.LocalUser = normalizedDocumentValue(e.value)
+		case "exec":
+			m.Exec, err = documentString(e.value, path+".exec", e.pos)
+		case "canonical":
+			m.Canonical, err = documentBool(e.value, path+".canonical", e.pos)
+		case "all":
+			m.All, err = documentBool(e.value, path+".all", e.pos)
+		case "position":
+			m.Position, err = documentString(e.value, path+".position", e.pos)
+		case "config":
+			m.RawConfigOrRef = normalizedDocumentValue(e.value)
+			if _, ok := m.RawConfigOrRef.([]interface{}); ok {
+				err = compiler.Errorf(e.pos, "invalid `%s.config`: expected a config name or properties", path)
+			}
+		default:
+			err = compiler.Errorf(e.pos, "unsupported key `%s.%s`, expected match criteria, `position` or `config`", path, e.key)
+		}
+		if err != nil {
+			return match{}, err
+		}
+	}
+	return m, nil
+}
+
+func documentValue(value interface{}, path string, pos compiler.Position) (document, error) {
+	if value == nil {
+		return document{}, nil
+	}
+	if doc, ok := value.(document); ok {
+		return doc, nil
+	}
+	return nil, compiler.Errorf(pos, "invalid `%s`: expected an object", path)
+}
+
+func documentString(value interface{}, path string, pos compiler.Position) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+	return "", compiler.Errorf(pos, "invalid `%s`: expected a string", path)
+}
+
+func documentBool(value interface{}, path string, pos compiler.Position) (bool, error) {
+	if value == nil {
+		return false, nil
+	}
+	if b, ok := value.(bool); ok {
+		return b, nil
+	}
+	return false, compiler.Errorf(pos, "invalid `%s`: expected a boolean", path)
+}
+
+func documentInt(value interface{}, path string, pos compiler.Position) (int, error) {
+	switch v := normalizedDocumentValue(value).(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, compiler.Errorf(pos, "invalid `%s`: expected a whole number", path)
+}
+
+// duplicateKey reports a key repeated in an object, otherwise the later value would silently replace the earlier one
+func (d document) duplicateKey(key string, pos compiler.Position) error {
+	for _, e := range d {
+		if e.key == key {
+			return compiler.Errorf(pos, "duplicate key `%s`", key)
+		}
+	}
+	return nil
+}
+
+func addDocumentPositions(positions sourcePositions, doc document, prefix []string) {
+	for _, e := range doc {
+		path := append(append([]string{}, prefix...), e.key)
+		positions.add(e.pos, path...)
+		if nested, ok := e.value.(document); ok {
+			addDocumentPositions(positions, nested, path)
+		}
+	}
+}
+
+// values returns a map of normalized values, nested documents become []map[string]interface{}
+func (d document) values() map[string]interface{} {
+	values := make(map[string]interface{}, len(d))
+	for _, e := range d {
+		values[e.key] = normalizedDocumentValue(e.value)
+	}
+	return values
+}
+
+func normalizedDocumentValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case document:
+		return []map[string]interface{}{v.values()}
+	case []interface{}:
+		normalized := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			normalized = append(normalized, normalizedDocumentValue(e))
+		}
+		return normalized
+	case int64:
+		return int(v)
+	}
+	return value
+}
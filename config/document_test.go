@@ -0,0 +1,165 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldDecodeDataFormatsPreservingHostsOrder(t *testing.T) {
+	t.Parallel()
+
+	// given
+	expected := rawFileContext{
+		Hosts: []host{{
+			Name:     "z",
+			Hostname: "z.example.com",
+			RawConfigOrRef: []map[string]interface{}{{
+				"port":          22,
+				"identity_file": []interface{}{"a.pem", "b.pem"},
+			}},
+		}, {
+			Name:           "a",
+			Alias:          "a",
+			RawConfigOrRef: "shared",
+		}},
+		RawConfigs: map[string]rawConfig{
+			"shared": {{"compression": true}},
+		},
+		Variables: map[string]interface{}{
+			"keys": []map[string]interface{}{{"a": "a.pem"}},
+		},
+	}
+	entries := []struct {
+		file     string
+		input    string
+		position compiler.Position
+	}{
+		{"example.yaml", `
+host:
+  z:
+    hostname: z.example.com
+    config: {port: 22, identity_file: [a.pem, b.pem]}
+  a:
+    alias: a
+    config: shared
+config:
+  shared:
+    compression: true
+var:
+  keys:
+    a: a.pem
+`, compiler.Position{Filename: "example.yaml", Line: 5, Column: 20}},
+		{"example.json", `{
+  "host": {
+    "z": {"hostname": "z.example.com", "config": {"port": 22, "identity_file": ["a.pem", "b.pem"]}},
+    "a": {"alias": "a", "config": "shared"}
+  },
+  "config": {"shared": {"compression": true}},
+  "var": {"keys": {"a": "a.pem"}}
+}`, compiler.Position{Filename: "example.json", Line: 3, Column: 59}},
+		{"example.toml", `
+[host.z]
+hostname = "z.example.com"
+config = { port = 22, identity_file = ["a.pem", "b.pem"] }
+
+[host.a]
+alias = "a"
+config = "shared"
+
+[config.shared]
+compression = true
+
+[var.keys]
+a = "a.pem"
+`, compiler.Position{Filename: "example.toml"}},
+	}
+
+	for _, e := range entries {
+		// when
+		ctx, err := newDecoder().decode(e.file, []byte(e.input))
+
+		// then
+		assert.NoError(t, err, e.file)
+		assert.Equal(t, e.position, ctx.positions.of("host", "z", "config", "port"), e.file)
+		ctx.positions = sourcePositions{}
+		assert.Equal(t, expected, ctx, e.file)
+	}
+}
+
+func TestShouldReturnErrorOnUnsupportedDocumentStructure(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		input    string
+		expected string
+	}{
+		{`hosts: {}`, "example.yaml:1:1: unsupported key `hosts`, expected `host`, `match`, `config` or `var`"},
+		{`host: [a, b]`, "example.yaml:1:1: invalid `host`: expected an object"},
+		{`host: {a: {alias: [a, b]}}`, "example.yaml:1:12: invalid `host.a.alias`: expected a string"},
+		{`host: {a: {alias: a, user: b}}`, "example.yaml:1:28: " +
+			"unsupported key `host.a.user`, expected `hostname`, `alias`, `priority` or `config`"},
+		{`host: {a: {alias: a, config: [b]}}`, "example.yaml:1:22: " +
+			"invalid `host.a.config`: expected a config name or properties"},
+		{`config: {a: b}`, "example.yaml:1:13: invalid `config.a`: expected an object"},
+	}
+
+	for _, e := range entries {
+		// when
+		_, err := decodeYAML("example.yaml", []byte(e.input))
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, e.expected, err.Error())
+	}
+}
+
+func TestShouldReturnErrorOnDuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		file     string
+		input    string
+		expected string
+	}{
+		{"example.yaml", `
+config:
+  a:
+    user: a
+host:
+  a:
+    alias: a
+config:
+  b:
+    user: b
+`, "example.yaml:8:1: duplicate key `config`"},
+		{"example.yaml", `host: {a: {alias: a, alias: b}}`, "example.yaml:1:22: duplicate key `alias`"},
+		{"example.json", `{
+  "config": {"a": {"user": "a"}},
+  "config": {"b": {"user": "b"}}
+}`, "example.json:3:3: duplicate key `config`"},
+	}
+
+	for _, e := range entries {
+		// when
+		_, err := newDecoder().decode(e.file, []byte(e.input))
+
+		// then
+		assert.Error(t, err, e.file)
+		assert.Equal(t, e.expected, err.Error(), e.file)
+	}
+}
+
+func TestShouldNotDecodeUnsupportedExtension(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, err := newDecoder().decode("example.ini", []byte{})
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, "unsupported file extension `.ini`", err.Error())
+}
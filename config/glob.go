@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const anyDirsSegment = "**"
+
+// globPattern is a shell-like file pattern, where `**` matches any number of directories.
+// Patterns without a slash are matched against the base name of a file or directory at any depth,
+// patterns ending with a slash match directories only.
+type globPattern struct {
+	segments []string
+	anchored bool
+	dirOnly  bool
+}
+
+func parseGlobPatterns(patterns []string) ([]globPattern, error) {
+	parsed := make([]globPattern, 0, len(patterns))
+	for _, p := range patterns {
+		g, err := parseGlobPattern(p)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, g)
+	}
+	return parsed, nil
+}
+
+func parseGlobPattern(pattern string) (globPattern, error) {
+	p := strings.TrimSpace(pattern)
+	g := globPattern{}
+	if strings.HasSuffix(p, "/") {
+		g.dirOnly = true
+		p = strings.TrimRight(p, "/")
+	}
+	if strings.Contains(p, "/") {
+		g.anchored = true
+		p = strings.TrimPrefix(p, "/")
+	}
+	if p == "" {
+		return globPattern{}, fmt.Errorf("invalid pattern `%s`", pattern)
+	}
+	g.segments = strings.Split(p, "/")
+	for _, s := range g.segments {
+		if _, err := path.Match(s, ""); err != nil {
+			return globPattern{}, fmt.Errorf("invalid pattern `%s`: %s", pattern, err.Error())
+		}
+	}
+	return g, nil
+}
+
+func (g globPattern) matches(relPath string, isDir bool) bool {
+	if g.dirOnly && !isDir {
+		return false
+	}
+	if !g.anchored {
+		matched, _ := path.Match(g.segments[0], path.Base(relPath))
+		return matched
+	}
+	return matchSegments(g.segments, strings.Split(relPath, "/"))
+}
+
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == anyDirsSegment {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+func matchesAny(patterns []globPattern, relPath string, isDir bool) bool {
+	for _, p := range patterns {
+		if p.matches(relPath, isDir) {
+			return true
+		}
+	}
+	return false
+}
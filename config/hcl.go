@@ -2,8 +2,10 @@ package config
 
 type rawFileContext struct {
 	Hosts      []host                 `hcl:"host"`
+	Matches    []match                `hcl:"match"`
 	RawConfigs map[string]rawConfig   `hcl:"config"`
 	Variables  map[string]interface{} `hcl:"var"`
+	positions  sourcePositions
 }
 
 type rawConfig []map[string]interface{}
@@ -12,5 +14,20 @@ type host struct {
 	Name           string      `hcl:",key"`
 	Hostname       string      `hcl:"hostname"`
 	Alias          string      `hcl:"alias"`
+	Priority       int         `hcl:"priority"`
+	RawConfigOrRef interface{} `hcl:"config"`
+}
+
+// match criteria of host, originalhost, user and localuser are patterns or lists of patterns
+type match struct {
+	Name           string      `hcl:",key"`
+	Host           interface{} `hcl:"host"`
+	OriginalHost   interface{} `hcl:"originalhost"`
+	User           interface{} `hcl:"user"`
+	LocalUser      interface{} `hcl:"localuser"`
+	Exec           string      `hcl:"exec"`
+	Canonical      bool        `hcl:"canonical"`
+	All            bool        `hcl:"all"`
+	Position       string      `hcl:"position"`
 	RawConfigOrRef interface{} `hcl:"config"`
 }
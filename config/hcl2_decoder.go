@@ -0,0 +1,553 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	hclExtension  = ".hcl"
+	hcl2Extension = ".hcl2"
+)
+
+// decodeHCL2 reads the same schema as HCL1 decoder, but using HCL2 native syntax.
+// Returned errors are compiler diagnostics positioned at problems reported by HCL2.
+func decodeHCL2(fileName string, input []byte) (rawFileContext, error) {
+	file, diags := hclsyntax.ParseConfig(input, fileName, hcl.InitialPos)
+	if diags.HasErrors() {
+		return rawFileContext{}, hcl2Error(fileName, diags)
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return rawFileContext{}, fmt.Errorf("unsupported HCL2 body in `%s`", fileName)
+	}
+	config := rawFileContext{
+		positions: newSourcePositions(fileName),
+	}
+	for _, attr := range sortedAttributes(body) {
+		diags = append(diags, unsupportedArgument(attr))
+	}
+	configRanges := map[string]hcl.Range{}
+	varRanges := map[string]hcl.Range{}
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "host":
+			h, blockDiags := decodeHCL2Host(block, config.positions)
+			diags = append(diags, blockDiags...)
+			config.Hosts = append(config.Hosts, h)
+		case "match":
+			m, blockDiags := decodeHCL2Match(block, config.positions)
+			diags = append(diags, blockDiags...)
+			config.Matches = append(config.Matches, m)
+		case "config":
+			if !hasLabels(block, 1, &diags) {
+				continue
+			}
+			if previous, ok := configRanges[block.Labels[0]]; ok {
+				diags = append(diags, duplicateDefinition(fmt.Sprintf("config %q", block.Labels[0]), previous, block.DefRange()))
+				continue
+			}
+			configRanges[block.Labels[0]] = block.DefRange()
+			config.positions.add(hcl2Position(block.DefRange()), "config", block.Labels[0])
+			props, blockDiags := hcl2BodyValues(block.Body, config.positions, "config", block.Labels[0])
+			diags = append(diags, blockDiags...)
+			if config.RawConfigs == nil {
+				config.RawConfigs = map[string]rawConfig{}
+			}
+			config.RawConfigs[block.Labels[0]] = rawConfig{props}
+		case "var":
+			if !hasLabels(block, 0, &diags) {
+				continue
+			}
+			config.positions.add(hcl2Position(block.DefRange()), "var")
+			vars, blockDiags := hcl2BodyValues(block.Body, config.positions, "var")
+			diags = append(diags, blockDiags...)
+			if config.Variables == nil {
+				config.Variables = map[string]interface{}{}
+			}
+			names := make([]string, 0, len(vars))
+			for name := range vars {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				r := hcl2DefinitionRange(block.Body, name)
+				if previous, ok := varRanges[name]; ok {
+					diags = append(diags, duplicateDefinition(fmt.Sprintf("variable %q", name), previous, r))
+					continue
+				}
+				varRanges[name] = r
+				config.Variables[name] = vars[name]
+			}
+		default:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported block type",
+				Detail:   fmt.Sprintf("Blocks of type %q are not expected here, use `host`, `match`, `config` or `var`.", block.Type),
+				Subject:  block.TypeRange.Ptr(),
+			})
+		}
+	}
+	if diags.HasErrors() {
+		return rawFileContext{}, hcl2Error(fileName, diags)
+	}
+	return config, nil
+}
+
+// hcl2Syntax tells whether input parses as HCL2 native syntax
+func hcl2Syntax(fileName string, input []byte) bool {
+	_, diags := hclsyntax.ParseConfig(input, fileName, hcl.InitialPos)
+	return !diags.HasErrors()
+}
+
+func decodeHCL2Host(block *hclsyntax.Block, positions sourcePositions) (host, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	if !hasLabels(block, 1, &diags) {
+		return host{}, diags
+	}
+	h := host{Name: block.Labels[0]}
+	positions.add(hcl2Position(block.DefRange()), "host", h.Name)
+	var configRange *hcl.Range
+	for _, attr := range sortedAttributes(block.Body) {
+		positions.add(hcl2Position(attr.Expr.Range()), "host", h.Name, attr.Name)
+		value, valueDiags := hcl2ExprValue(attr.Expr, positions, "host", h.Name, attr.Name)
+		diags = append(diags, valueDiags...)
+		switch attr.Name {
+		case "hostname":
+			h.Hostname = hcl2String(attr, value, &diags)
+		case "alias":
+			h.Alias = hcl2String(attr, value, &diags)
+		case "priority":
+			h.Priority = hcl2Int(attr, value, &diags)
+		case "config":
+			h.RawConfigOrRef = value
+			configRange = attr.SrcRange.Ptr()
+		default:
+			diags = append(diags, unsupportedArgument(attr))
+		}
+	}
+	if props, ok := hcl2ConfigBlock(block, positions, "host", h.Name, configRange, &diags); ok {
+		h.RawConfigOrRef = props
+	}
+	return h, diags
+}
+
+func decodeHCL2Match(block *hclsyntax.Block, positions sourcePositions) (match, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	if !hasLabels(block, 1, &diags) {
+		return match{}, diags
+	}
+	m := match{Name: block.Labels[0]}
+	positions.add(hcl2Position(block.DefRange()), "match", m.Name)
+	var configRange *hcl.Range
+	for _, attr := range sortedAttributes(block.Body) {
+		positions.add(hcl2Position(attr.Expr.Range()), "match", m.Name, attr.Name)
+		value, valueDiags := hcl2ExprValue(attr.Expr, positions, "match", m.Name, attr.Name)
+		diags = append(diags, valueDiags...)
+		switch attr.Name {
+		case "host":
+			m.Host = value
+		case "originalhost":
+			m.OriginalHost = value
+		case "user":
+			m.User = value
+		case "localuser":
+			m.LocalUser = value
+		case "exec":
+			m.Exec = hcl2String(attr, value, &diags)
+		case "canonical":
+			m.Canonical = hcl2Bool(attr, value, &diags)
+		case "all":
+			m.All = hcl2Bool(attr, value, &diags)
+		case "position":
+			m.Position = hcl2String(attr, value, &diags)
+		case "config":
+			m.RawConfigOrRef = value
+			configRange = attr.SrcRange.Ptr()
+		default:
+			diags = append(diags, unsupportedArgument(attr))
+		}
+	}
+	if props, ok := hcl2ConfigBlock(block, positions, "match", m.Name, configRange, &diags); ok {
+		m.RawConfigOrRef = props
+	}
+	return m, diags
+}
+
+// hcl2ConfigBlock reads properties of `config` block nested in a host or match definition,
+// the config may be defined only once, either by the `config` attribute or by a single block
+func hcl2ConfigBlock(block *hclsyntax.Block, positions sourcePositions, kind string, name string,
+	defined *hcl.Range, diags *hcl.Diagnostics) ([]map[string]interface{}, bool) {
+	var config []map[string]interface{}
+	for _, child := range block.Body.Blocks {
+		if child.Type != "config" {
+			*diags = append(*diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported block type",
+				Detail:   fmt.Sprintf("Blocks of type %q are not expected in %s definition.", child.Type, kind),
+				Subject:  child.TypeRange.Ptr(),
+			})
+			continue
+		}
+		if !hasLabels(child, 0, diags) {
+			continue
+		}
+		if defined != nil {
+			*diags = append(*diags, duplicateDefinition(fmt.Sprintf("config of %s %q", kind, name), *defined, child.DefRange()))
+			continue
+		}
+		defined = child.DefRange().Ptr()
+		positions.add(hcl2Position(child.DefRange()), kind, name, "config")
+		props, childDiags := hcl2BodyValues(child.Body, positions, kind, name, "config")
+		*diags = append(*diags, childDiags...)
+		config = []map[string]interface{}{props}
+	}
+	return config, config != nil
+}
+
+// hcl2BodyValues converts attributes and nested blocks into the structure produced by HCL1 decoder,
+// repeated blocks of the same type are collected into a list, like multiple `local_forward` blocks
+func hcl2BodyValues(body *hclsyntax.Body, positions sourcePositions, path ...string) (map[string]interface{}, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	values := map[string]interface{}{}
+	attrRanges := map[string]hcl.Range{}
+	for _, attr := range sortedAttributes(body) {
+		attrRanges[attr.Name] = attr.SrcRange
+		attrPath := append(append([]string{}, path...), attr.Name)
+		positions.add(hcl2Position(attr.Expr.Range()), attrPath...)
+		value, valueDiags := hcl2ExprValue(attr.Expr, positions, attrPath...)
+		diags = append(diags, valueDiags...)
+		values[attr.Name] = value
+	}
+	for _, block := range body.Blocks {
+		if !hasLabels(block, 0, &diags) {
+			continue
+		}
+		if attrRange, ok := attrRanges[block.Type]; ok {
+			diags = append(diags, duplicateDefinition(fmt.Sprintf("property %q", block.Type), attrRange, block.DefRange()))
+			continue
+		}
+		blockPath := append(append([]string{}, path...), block.Type)
+		blocks, repeated := values[block.Type].([]map[string]interface{})
+		if !repeated {
+			positions.add(hcl2Position(block.DefRange()), blockPath...)
+		}
+		nested, blockDiags := hcl2BodyValues(block.Body, positions, blockPath...)
+		diags = append(diags, blockDiags...)
+		values[block.Type] = append(blocks, nested)
+	}
+	return values, diags
+}
+
+func hcl2ExprValue(expr hclsyntax.Expression, positions sourcePositions, path ...string) (interface{}, hcl.Diagnostics) {
+	switch e := expr.(type) {
+	case *hclsyntax.TemplateExpr:
+		return hcl2Template(e.Parts)
+	case *hclsyntax.TemplateWrapExpr:
+		return hcl2Template([]hclsyntax.Expression{e.Wrapped})
+	case *hclsyntax.ScopeTraversalExpr:
+		// a bare reference, like `user = users.a`, is an equivalent of "${users.a}"
+		return hcl2Template([]hclsyntax.Expression{e})
+	case *hclsyntax.ObjectConsExpr:
+		var diags hcl.Diagnostics
+		values := map[string]interface{}{}
+		for _, item := range e.Items {
+			key := hcl.ExprAsKeyword(item.KeyExpr)
+			if key == "" {
+				keyValue, keyDiags := item.KeyExpr.Value(nil)
+				diags = append(diags, keyDiags...)
+				if keyDiags.HasErrors() || keyValue.IsNull() || keyValue.Type() != cty.String {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid object key",
+						Detail:   "Object keys must be names or strings.",
+						Subject:  item.KeyExpr.Range().Ptr(),
+					})
+					continue
+				}
+				key = keyValue.AsString()
+			}
+			itemPath := append(append([]string{}, path...), key)
+			positions.add(hcl2Position(item.ValueExpr.Range()), itemPath...)
+			value, valueDiags := hcl2ExprValue(item.ValueExpr, positions, itemPath...)
+			diags = append(diags, valueDiags...)
+			values[key] = value
+		}
+		return []map[string]interface{}{values}, diags
+	case *hclsyntax.TupleConsExpr:
+		var diags hcl.Diagnostics
+		values := make([]interface{}, 0, len(e.Exprs))
+		for _, item := range e.Exprs {
+			value, valueDiags := hcl2ExprValue(item, positions, path...)
+			diags = append(diags, valueDiags...)
+			values = append(values, value)
+		}
+		return values, diags
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	converted, err := ctyToInterface(value)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported value",
+			Detail:   err.Error(),
+			Subject:  expr.Range().Ptr(),
+		})
+	}
+	return converted, diags
+}
+
+// hcl2Template keeps variable references as ${name} placeholders, so they are interpolated
+// the same way as in other formats, static expressions are evaluated
+func hcl2Template(parts []hclsyntax.Expression) (interface{}, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	var str strings.Builder
+	for _, part := range parts {
+		if traversal, ok := part.(*hclsyntax.ScopeTraversalExpr); ok {
+			name, err := traversalName(traversal.Traversal)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unsupported variable reference",
+					Detail:   err.Error(),
+					Subject:  traversal.Range().Ptr(),
+				})
+				continue
+			}
+			str.WriteString("${" + name + "}")
+			continue
+		}
+		value, partDiags := part.Value(nil)
+		diags = append(diags, partDiags...)
+		if partDiags.HasErrors() {
+			continue
+		}
+		converted, err := ctyToInterface(value)
+		if err != nil || !isScalar(converted) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported value",
+				Detail:   "Only strings, numbers and booleans can be used in templates.",
+				Subject:  part.Range().Ptr(),
+			})
+			continue
+		}
+		if len(parts) == 1 {
+			return converted, diags
+		}
+		str.WriteString(fmt.Sprintf("%v", converted))
+	}
+	return str.String(), diags
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case nil, string, int, float64, bool:
+		return true
+	}
+	return false
+}
+
+func traversalName(traversal hcl.Traversal) (string, error) {
+	names := make([]string, 0, len(traversal))
+	for _, t := range traversal {
+		switch step := t.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		default:
+			return "", fmt.Errorf("variables can be referenced only by their dot separated names")
+		}
+	}
+	return strings.Join(names, "."), nil
+}
+
+func ctyToInterface(value cty.Value) (interface{}, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+	if !value.IsWhollyKnown() {
+		return nil, fmt.Errorf("value is not known")
+	}
+	valueType := value.Type()
+	switch {
+	case valueType == cty.String:
+		return value.AsString(), nil
+	case valueType == cty.Bool:
+		return value.True(), nil
+	case valueType == cty.Number:
+		bf := value.AsBigFloat()
+		if bf.IsInt() {
+			if i, accuracy := bf.Int64(); accuracy == big.Exact {
+				return int(i), nil
+			}
+		}
+		f, _ := bf.Float64()
+		return f, nil
+	case valueType.IsListType() || valueType.IsTupleType() || valueType.IsSetType():
+		values := make([]interface{}, 0, value.LengthInt())
+		for it := value.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			converted, err := ctyToInterface(v)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, converted)
+		}
+		return values, nil
+	case valueType.IsMapType() || valueType.IsObjectType():
+		values := map[string]interface{}{}
+		for it := value.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			converted, err := ctyToInterface(v)
+			if err != nil {
+				return nil, err
+			}
+			values[k.AsString()] = converted
+		}
+		return []map[string]interface{}{values}, nil
+	}
+	return nil, fmt.Errorf("values of type %s are not supported", valueType.FriendlyName())
+}
+
+func hcl2String(attr *hclsyntax.Attribute, value interface{}, diags *hcl.Diagnostics) string {
+	if value == nil {
+		return ""
+	}
+	if str, ok := value.(string); ok {
+		return str
+	}
+	*diags = append(*diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Incorrect attribute value type",
+		Detail:   fmt.Sprintf("Attribute %q must be a string.", attr.Name),
+		Subject:  attr.Expr.Range().Ptr(),
+	})
+	return ""
+}
+
+func hcl2Bool(attr *hclsyntax.Attribute, value interface{}, diags *hcl.Diagnostics) bool {
+	if value == nil {
+		return false
+	}
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	*diags = append(*diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Incorrect attribute value type",
+		Detail:   fmt.Sprintf("Attribute %q must be a boolean.", attr.Name),
+		Subject:  attr.Expr.Range().Ptr(),
+	})
+	return false
+}
+
+func hcl2Int(attr *hclsyntax.Attribute, value interface{}, diags *hcl.Diagnostics) int {
+	if value == nil {
+		return 0
+	}
+	if i, ok := value.(int); ok {
+		return i
+	}
+	*diags = append(*diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Incorrect attribute value type",
+		Detail:   fmt.Sprintf("Attribute %q must be a whole number.", attr.Name),
+		Subject:  attr.Expr.Range().Ptr(),
+	})
+	return 0
+}
+
+func hasLabels(block *hclsyntax.Block, expected int, diags *hcl.Diagnostics) bool {
+	if len(block.Labels) == expected {
+		return true
+	}
+	detail := fmt.Sprintf("A %q block must have exactly %d label(s).", block.Type, expected)
+	if expected == 0 {
+		detail = fmt.Sprintf("A %q block must not have labels.", block.Type)
+	}
+	*diags = append(*diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid block labels",
+		Detail:   detail,
+		Subject:  block.DefRange().Ptr(),
+	})
+	return false
+}
+
+// hcl2DefinitionRange returns where a property of a body is defined, either as an attribute or as the first nested block
+func hcl2DefinitionRange(body *hclsyntax.Body, name string) hcl.Range {
+	if attr, ok := body.Attributes[name]; ok {
+		return attr.SrcRange
+	}
+	for _, block := range body.Blocks {
+		if block.Type == name {
+			return block.DefRange()
+		}
+	}
+	return body.SrcRange
+}
+
+func duplicateDefinition(what string, previous hcl.Range, duplicate hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Duplicate definition",
+		Detail:   fmt.Sprintf("The %s was already defined at %s, it may be defined only once.", what, previous),
+		Subject:  duplicate.Ptr(),
+	}
+}
+
+func unsupportedArgument(attr *hclsyntax.Attribute) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unsupported argument",
+		Detail:   fmt.Sprintf("An argument named %q is not expected here.", attr.Name),
+		Subject:  attr.NameRange.Ptr(),
+	}
+}
+
+func sortedAttributes(body *hclsyntax.Body) []*hclsyntax.Attribute {
+	attrs := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, attr := range body.Attributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+	})
+	return attrs
+}
+
+func hcl2Position(r hcl.Range) compiler.Position {
+	return compiler.Position{Filename: r.Filename, Line: r.Start.Line, Column: r.Start.Column}
+}
+
+// hcl2Error converts error diagnostics into compiler diagnostics positioned at reported problems
+func hcl2Error(fileName string, diags hcl.Diagnostics) error {
+	var converted compiler.Diagnostics
+	for _, d := range diags {
+		if d.Severity != hcl.DiagError {
+			continue
+		}
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += "; " + d.Detail
+		}
+		pos := compiler.Position{Filename: fileName}
+		if d.Subject != nil {
+			pos = hcl2Position(*d.Subject)
+		}
+		converted = append(converted, compiler.Diagnostic{Pos: pos, Msg: msg})
+	}
+	return converted
+}
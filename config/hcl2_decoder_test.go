@@ -0,0 +1,161 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldDecodeHCL2(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := []byte(`
+host "a" {
+  hostname = "a[1..2].${dc}"
+  alias    = "a{#1}"
+  config {
+    port     = 2222
+    send_env = ["LANG", "LC_*"]
+  }
+}
+
+config "b" {
+  user = "${users.b}"
+}
+
+var {
+  dc = "dc1.example.com"
+  users = {
+    b = "helix"
+  }
+}
+`)
+
+	// when
+	ctx, err := decodeHCL2("example.hcl2", input)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.Position{Filename: "example.hcl2", Line: 2, Column: 1}, ctx.positions.of("host", "a"))
+	assert.Equal(t, compiler.Position{Filename: "example.hcl2", Line: 6, Column: 16}, ctx.positions.of("host", "a", "config", "port"))
+	assert.Equal(t, compiler.Position{Filename: "example.hcl2", Line: 18, Column: 9}, ctx.positions.of("var", "users", "b"))
+	ctx.positions = sourcePositions{}
+	assert.Equal(t, rawFileContext{
+		Hosts: []host{{
+			Name:     "a",
+			Hostname: "a[1..2].${dc}",
+			Alias:    "a{#1}",
+			RawConfigOrRef: []map[string]interface{}{{
+				"port":     2222,
+				"send_env": []interface{}{"LANG", "LC_*"},
+			}},
+		}},
+		RawConfigs: map[string]rawConfig{
+			"b": {{"user": "${users.b}"}},
+		},
+		Variables: map[string]interface{}{
+			"dc":    "dc1.example.com",
+			"users": []map[string]interface{}{{"b": "helix"}},
+		},
+	}, ctx)
+}
+
+func TestShouldReturnDiagnosticsForUnsupportedHCL2Expressions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		input    string
+		expected string
+	}{
+		{`config "a" {
+  user = upper("eden")
+}`, "example.hcl2:2:10: Function calls not allowed; Functions may not be called here."},
+		{`host "a" {
+  hostname = "a.example.com"
+  alias = ["a"]
+}`, "example.hcl2:3:11: Incorrect attribute value type; Attribute \"alias\" must be a string."},
+		{`config "a" {
+  user = "${users[0]}"
+}`, "example.hcl2:2:13: Unsupported variable reference; " +
+			"variables can be referenced only by their dot separated names"},
+		{`alias "a" {}`, "example.hcl2:1:1: Unsupported block type; " +
+			"Blocks of type \"alias\" are not expected here, use `host`, `match`, `config` or `var`."},
+		{`host "a" {
+  config {
+    user = "a"
+  }
+  config {
+    user = "b"
+  }
+}`, "example.hcl2:5:3: Duplicate definition; " +
+			"The config of host \"a\" was already defined at example.hcl2:2,3-9, it may be defined only once."},
+		{`match "a" {
+  host = "*.example.com"
+  config = "defaults"
+  config {
+    user = "b"
+  }
+}`, "example.hcl2:4:3: Duplicate definition; " +
+			"The config of match \"a\" was already defined at example.hcl2:3,3-22, it may be defined only once."},
+		{`config "a" {
+  user = "a"
+}
+config "a" {
+  user = "b"
+}`, "example.hcl2:4:1: Duplicate definition; " +
+			"The config \"a\" was already defined at example.hcl2:1,1-11, it may be defined only once."},
+		{`config "a" {
+  local_forward = "8080 localhost:80"
+  local_forward {
+    bind = 8443
+  }
+}`, "example.hcl2:3:3: Duplicate definition; " +
+			"The property \"local_forward\" was already defined at example.hcl2:2,3-38, it may be defined only once."},
+		{`var {
+  domain = "example.com"
+}
+var {
+  domain = "example.org"
+}`, "example.hcl2:5:3: Duplicate definition; " +
+			"The variable \"domain\" was already defined at example.hcl2:2,3-25, it may be defined only once."},
+	}
+
+	for _, e := range entries {
+		// when
+		_, err := decodeHCL2("example.hcl2", []byte(e.input))
+
+		// then
+		assert.Error(t, err)
+		assert.Equal(t, e.expected, err.Error())
+	}
+}
+
+func TestShouldCollectRepeatedHCL2BlocksIntoList(t *testing.T) {
+	t.Parallel()
+
+	// given
+	input := []byte(`
+config "a" {
+  local_forward {
+    bind = 8080
+  }
+  local_forward {
+    bind = 8443
+  }
+}
+`)
+
+	// when
+	ctx, err := decodeHCL2("example.hcl2", input)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.Position{Filename: "example.hcl2", Line: 3, Column: 3},
+		ctx.positions.of("config", "a", "local_forward"))
+	assert.Equal(t, rawConfig{{
+		"local_forward": []map[string]interface{}{{"bind": 8080}, {"bind": 8443}},
+	}}, ctx.RawConfigs["a"])
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+)
+
+func decodeJSON(fileName string, input []byte) (rawFileContext, error) {
+	if len(bytes.TrimSpace(input)) == 0 {
+		return rawFileContext{}, nil
+	}
+	d := &jsonDecoder{
+		decoder:  json.NewDecoder(bytes.NewReader(input)),
+		fileName: fileName,
+		input:    input,
+	}
+	d.decoder.UseNumber()
+	value, err := d.value()
+	if err != nil {
+		return rawFileContext{}, err
+	}
+	if _, err = d.decoder.Token(); err != io.EOF {
+		return rawFileContext{}, fmt.Errorf("unexpected data after top-level value")
+	}
+	doc, err := documentValue(value, fileName, compiler.Position{})
+	if err != nil {
+		return rawFileContext{}, err
+	}
+	return documentContext(fileName, doc)
+}
+
+type jsonDecoder struct {
+	decoder  *json.Decoder
+	fileName string
+	input    []byte
+}
+
+// value reads values token by token, so the order of object keys is preserved
+func (d *jsonDecoder) value() (interface{}, error) {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			doc := document{}
+			for d.decoder.More() {
+				keyPos := d.position()
+				key, err := d.decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				if err := doc.duplicateKey(key.(string), keyPos); err != nil {
+					return nil, err
+				}
+				valuePos := d.position()
+				value, err := d.value()
+				if err != nil {
+					return nil, err
+				}
+				pos := valuePos
+				if _, ok := value.(document); ok {
+					pos = keyPos
+				} else if _, ok := value.([]interface{}); ok {
+					pos = keyPos
+				}
+				doc = append(doc, documentEntry{key.(string), value, pos})
+			}
+			_, err = d.decoder.Token()
+			return doc, err
+		case '[':
+			values := []interface{}{}
+			for d.decoder.More() {
+				value, err := d.value()
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			_, err = d.decoder.Token()
+			return values, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter `%v`", t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return int(i), nil
+		}
+		return t.Float64()
+	}
+	return token, nil
+}
+
+// position returns the location of the next token, skipping whitespace and separators
+func (d *jsonDecoder) position() compiler.Position {
+	offset := int(d.decoder.InputOffset())
+	for offset < len(d.input) && bytes.IndexByte([]byte(" \t\r\n,:"), d.input[offset]) >= 0 {
+		offset++
+	}
+	line := bytes.Count(d.input[:offset], []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(d.input[:offset], '\n') + 1
+	return compiler.Position{
+		Filename: d.fileName,
+		Line:     line,
+		Column:   utf8.RuneCount(d.input[lineStart:offset]) + 1,
+	}
+}
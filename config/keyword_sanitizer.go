@@ -2,12 +2,56 @@ package config
 
 import (
 	"strings"
+	"unicode"
+
+	"github.com/dankraw/ssh-aliases/keywords"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
+// sanitize converts a keyword of ssh-aliases config into its ssh config form, keywords found in the catalog
+// are spelled as in ssh_config(5), other keywords are title-cased and their underscores are removed
 func sanitize(keyword string) string {
+	if known, ok := keywords.Lookup(keyword); ok {
+		return known.Name
+	}
 	withSpaces := strings.ReplaceAll(keyword, "_", " ")
 	titled := cases.Title(language.English, cases.NoLower).String(withSpaces)
 	return strings.ReplaceAll(titled, " ", "")
 }
+
+// keywords that cannot be split into words by their casing
+var desanitizeExceptions = map[string]string{
+	"ipqos": "ipqos",
+}
+
+// Desanitize converts ssh config keyword into its snake case form used in ssh-aliases configs,
+// so that sanitizing the result gives a keyword recognized by ssh (keywords are case insensitive)
+func Desanitize(keyword string) string {
+	if exception, ok := desanitizeExceptions[strings.ToLower(keyword)]; ok {
+		return exception
+	}
+	runes := []rune(keyword)
+	var result strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && startsWord(runes, i) {
+			result.WriteRune('_')
+		}
+		result.WriteRune(unicode.ToLower(r))
+	}
+	return result.String()
+}
+
+// startsWord tells if the upper case rune at position i begins a new word, acronyms like `GSSAPI` are kept together
+// and a trailing `s` is treated as a plural form of an acronym, like in `MACs`
+func startsWord(runes []rune, i int) bool {
+	prev := runes[i-1]
+	if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+		return true
+	}
+	if i+1 >= len(runes) || !unicode.IsLower(runes[i+1]) {
+		return false
+	}
+	pluralAcronym := runes[i+1] == 's' && (i+2 == len(runes) || unicode.IsUpper(runes[i+2]))
+	return !pluralAcronym
+}
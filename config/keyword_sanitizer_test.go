@@ -19,6 +19,13 @@ func TestShouldSanitizeKeywords(t *testing.T) {
 		{"hash_known_hosts", "HashKnownHosts"},
 		{"MACs", "MACs"},
 		{"RhostsRSAAuthentication", "RhostsRSAAuthentication"},
+		{"gssapi_authentication", "GSSAPIAuthentication"},
+		{"macs", "MACs"},
+		{"kexalgorithms", "KexAlgorithms"},
+		{"ipqos", "IPQoS"},
+		{"hostname", "HostName"},
+		{"tcp_keep_alive", "TCPKeepAlive"},
+		{"use_keychain", "UseKeychain"},
 	}
 
 	for _, e := range entries {
@@ -29,3 +36,30 @@ func TestShouldSanitizeKeywords(t *testing.T) {
 		assert.Equal(t, actual, e.expected)
 	}
 }
+
+func TestShouldDesanitizeKeywords(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		input    string
+		expected string
+	}{
+		{"IdentityFile", "identity_file"},
+		{"port", "port"},
+		{"HashKnownHosts", "hash_known_hosts"},
+		{"MACs", "macs"},
+		{"RhostsRSAAuthentication", "rhosts_rsa_authentication"},
+		{"GSSAPIAuthentication", "gssapi_authentication"},
+		{"IPQoS", "ipqos"},
+		{"ForwardX11Trusted", "forward_x11_trusted"},
+	}
+
+	for _, e := range entries {
+		// when
+		actual := Desanitize(e.input)
+
+		// then
+		assert.Equal(t, e.expected, actual)
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+	"github.com/dankraw/ssh-aliases/keywords"
+)
+
+// KeywordOptions tell how keywords of config properties are checked against the catalog of ssh config keywords
+type KeywordOptions struct {
+	Strictness keywords.Strictness
+	// Allowed are custom keywords accepted regardless of strictness, like keywords of patched ssh builds
+	Allowed []string
+}
+
+func (o KeywordOptions) allowed(keyword string) bool {
+	for _, a := range o.Allowed {
+		if strings.EqualFold(sanitize(a), sanitize(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkKeywords reports keywords of config properties that are unknown or deprecated
+func checkKeywords(sources []rawContextSource, options KeywordOptions, diagnostics *compiler.Collector) {
+	if options.Strictness == keywords.Off {
+		return
+	}
+	for _, s := range sources {
+		positions := s.RawContext.positions
+		for _, name := range sortedKeys(s.RawContext.RawConfigs) {
+			for _, props := range s.RawContext.RawConfigs[name] {
+				if checkPropertyKeywords(props, options, positions, diagnostics, "config", name) {
+					return
+				}
+			}
+		}
+		for _, h := range s.RawContext.Hosts {
+			if props, ok := h.RawConfigOrRef.([]map[string]interface{}); ok {
+				for _, p := range props {
+					if checkPropertyKeywords(p, options, positions, diagnostics, "host", h.Name, "config") {
+						return
+					}
+				}
+			}
+		}
+		for _, m := range s.RawContext.Matches {
+			if props, ok := m.RawConfigOrRef.([]map[string]interface{}); ok {
+				for _, p := range props {
+					if checkPropertyKeywords(p, options, positions, diagnostics, "match", m.Name, "config") {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// checkPropertyKeywords checks keywords of properties declared at the given path, returns true if processing should stop
+func checkPropertyKeywords(props map[string]interface{}, options KeywordOptions, positions sourcePositions,
+	diagnostics *compiler.Collector, path ...string) bool {
+	definition := fmt.Sprintf("`%s` %s definition", path[1], path[0])
+	for _, k := range sortedPropertyKeys(props) {
+		if k == extendConfigKey || options.allowed(k) {
+			continue
+		}
+		pos := positions.of(append(path, k)...)
+		keyword, ok := keywords.Lookup(k)
+		if !ok {
+			msg := fmt.Sprintf("unknown keyword `%s` in %s", k, definition)
+			if suggested, ok := keywords.Suggest(k); ok {
+				msg += fmt.Sprintf(", did you mean `%s`?", styledLike(k, suggested.Name))
+			}
+			if options.Strictness == keywords.Error {
+				if diagnostics.Add(compiler.Errorf(pos, "%s", msg)) {
+					return true
+				}
+				continue
+			}
+			diagnostics.Warnf(pos, "%s", msg)
+			continue
+		}
+		if keyword.Deprecated != "" {
+			msg := fmt.Sprintf("keyword `%s` in %s is deprecated since OpenSSH %s", k, definition, keyword.Deprecated)
+			if keyword.ReplacedBy != "" {
+				msg += fmt.Sprintf(", use `%s` instead", styledLike(k, keyword.ReplacedBy))
+			}
+			diagnostics.Warnf(pos, "%s", msg)
+		}
+	}
+	return false
+}
+
+// styledLike writes a catalog keyword in the style of the declared one, snake case or as in ssh_config
+func styledLike(declared string, keyword string) string {
+	if strings.Contains(declared, "_") || strings.ToLower(declared) == declared {
+		return Desanitize(keyword)
+	}
+	return keyword
+}
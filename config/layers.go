@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+)
+
+// override describes a definition from a lower layer replaced by a definition from a higher layer
+type override struct {
+	Kind             string
+	Name             string
+	SourceName       string
+	OverriddenSource string
+}
+
+func (o override) String() string {
+	return fmt.Sprintf("%s `%s` from `%s` is overridden by `%s`", o.Kind, o.Name, o.OverriddenSource, o.SourceName)
+}
+
+type definitionSource struct {
+	layer      int
+	sourceName string
+}
+
+// resolveLayers makes sure host, match and config names are unique within each layer (scanned directory)
+// and removes definitions that are redefined in higher layers, duplicates within a layer are reported and skipped
+func resolveLayers(sources []rawContextSource, diagnostics *compiler.Collector) ([]rawContextSource, []override) {
+	hosts := map[string]definitionSource{}
+	matches := map[string]definitionSource{}
+	configs := map[string]definitionSource{}
+	var overrides []override
+	duplicates := false
+	for _, s := range sources {
+		for _, h := range s.RawContext.Hosts {
+			if defined, contains := hosts[h.Name]; contains {
+				if defined.layer == s.Layer {
+					duplicates = true
+					if diagnostics.Add(compiler.Errorf(s.RawContext.positions.of("host", h.Name), "duplicate host `%v`", h.Name)) {
+						return nil, nil
+					}
+					continue
+				}
+				overrides = append(overrides, override{"host", h.Name, s.SourceName, defined.sourceName})
+			}
+			hosts[h.Name] = definitionSource{s.Layer, s.SourceName}
+		}
+		for _, m := range s.RawContext.Matches {
+			if defined, contains := matches[m.Name]; contains {
+				if defined.layer == s.Layer {
+					duplicates = true
+					if diagnostics.Add(compiler.Errorf(s.RawContext.positions.of("match", m.Name), "duplicate match `%v`", m.Name)) {
+						return nil, nil
+					}
+					continue
+				}
+				overrides = append(overrides, override{"match", m.Name, s.SourceName, defined.sourceName})
+			}
+			matches[m.Name] = definitionSource{s.Layer, s.SourceName}
+		}
+		for _, name := range sortedKeys(s.RawContext.RawConfigs) {
+			if defined, contains := configs[name]; contains {
+				if defined.layer == s.Layer {
+					duplicates = true
+					if diagnostics.Add(compiler.Errorf(s.RawContext.positions.of("config", name), "duplicate config `%v`", name)) {
+						return nil, nil
+					}
+					continue
+				}
+				overrides = append(overrides, override{"config", name, s.SourceName, defined.sourceName})
+			}
+			configs[name] = definitionSource{s.Layer, s.SourceName}
+		}
+	}
+	if len(overrides) == 0 && !duplicates {
+		return sources, nil
+	}
+	resolved := make([]rawContextSource, 0, len(sources))
+	for _, s := range sources {
+		ctx := s.RawContext
+		ctx.Hosts = []host{}
+		ctx.Matches = []match{}
+		ctx.RawConfigs = map[string]rawConfig{}
+		defined := definitionSource{s.Layer, s.SourceName}
+		kept := map[string]bool{}
+		for _, h := range s.RawContext.Hosts {
+			if hosts[h.Name] == defined && !kept[h.Name] {
+				ctx.Hosts = append(ctx.Hosts, h)
+				kept[h.Name] = true
+			}
+		}
+		keptMatches := map[string]bool{}
+		for _, m := range s.RawContext.Matches {
+			if matches[m.Name] == defined && !keptMatches[m.Name] {
+				ctx.Matches = append(ctx.Matches, m)
+				keptMatches[m.Name] = true
+			}
+		}
+		for name, c := range s.RawContext.RawConfigs {
+			if configs[name] == defined {
+				ctx.RawConfigs[name] = c
+			}
+		}
+		resolved = append(resolved, rawContextSource{
+			SourceName: s.SourceName,
+			Layer:      s.Layer,
+			RawContext: ctx,
+		})
+	}
+	return resolved, overrides
+}
+
+func sortedKeys(configs map[string]rawConfig) []string {
+	keys := make([]string, 0, len(configs))
+	for k := range configs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
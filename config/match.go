@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+)
+
+const (
+	matchPositionTop    = "top"
+	matchPositionBottom = "bottom"
+)
+
+func matchConfigs(fileCtx rawFileContext, variables variablesMap, propsMap map[string]configProps,
+	diagnostics *compiler.Collector) []compiler.MatchConfig {
+	var inputs []compiler.MatchConfig
+	for _, m := range fileCtx.Matches {
+		input, err := matchConfig(m, fileCtx.positions, variables, propsMap)
+		if err != nil {
+			if diagnostics.Add(err) {
+				return nil
+			}
+			continue
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+// matchConfig converts a match definition into Match section, criteria are ordered the way ssh expects them,
+// with `canonical` and `all` first
+func matchConfig(m match, positions sourcePositions, variables variablesMap,
+	configsMap map[string]configProps) (compiler.MatchConfig, error) {
+	pos := positions.of("match", m.Name)
+	var errs compiler.Diagnostics
+	var criteria []compiler.MatchCriterion
+	if m.Canonical {
+		criteria = append(criteria, compiler.MatchCriterion{Keyword: "canonical"})
+	}
+	if m.All {
+		criteria = append(criteria, compiler.MatchCriterion{Keyword: "all"})
+	}
+	for _, c := range []struct {
+		keyword string
+		value   interface{}
+	}{
+		{"host", m.Host},
+		{"originalhost", m.OriginalHost},
+		{"user", m.User},
+		{"localuser", m.LocalUser},
+		{"exec", m.Exec},
+	} {
+		criterionPos := positions.of("match", m.Name, c.keyword)
+		value, err := matchCriterionValue(c.value, variables)
+		if err != nil {
+			errs = append(errs, compiler.Diagnostic{Pos: criterionPos,
+				Msg: fmt.Sprintf("error in `%s` criterion of `%s` match definition: %s", c.keyword, m.Name, err.Error())})
+			continue
+		}
+		if value != "" {
+			criteria = append(criteria, compiler.MatchCriterion{Keyword: c.keyword, Value: value})
+		}
+	}
+	if len(errs) > 0 {
+		return compiler.MatchConfig{}, errs
+	}
+	if len(criteria) == 0 {
+		return compiler.MatchConfig{}, compiler.Errorf(pos, "invalid `%s` match definition: no criteria specified", m.Name)
+	}
+	if m.All && len(criteria) > 1 && !(m.Canonical && len(criteria) == 2) {
+		return compiler.MatchConfig{}, compiler.Errorf(pos,
+			"invalid `%s` match definition: `all` criterion can be combined only with `canonical`", m.Name)
+	}
+	top := false
+	switch m.Position {
+	case matchPositionTop:
+		top = true
+	case matchPositionBottom, "":
+	default:
+		return compiler.MatchConfig{}, compiler.Errorf(positions.of("match", m.Name, "position"),
+			"invalid `%s` match definition: position `%s` is not one of `%s` or `%s`",
+			m.Name, m.Position, matchPositionTop, matchPositionBottom)
+	}
+	config, err := definitionConfig("match", m.Name, m.RawConfigOrRef, positions, variables, configsMap)
+	if err != nil {
+		return compiler.MatchConfig{}, err
+	}
+	if config == nil {
+		return compiler.MatchConfig{}, compiler.Errorf(pos, "no config specified for match `%s`", m.Name)
+	}
+	return compiler.MatchConfig{
+		Name:     m.Name,
+		Criteria: criteria,
+		Config:   config,
+		Top:      top,
+		Position: pos,
+	}, nil
+}
+
+// matchCriterionValue interpolates a pattern, or joins a list of patterns with commas
+func matchCriterionValue(value interface{}, variables variablesMap) (string, error) {
+	var patterns []string
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		patterns = []string{v}
+	case []interface{}:
+		for _, p := range v {
+			str, ok := p.(string)
+			if !ok {
+				return "", fmt.Errorf("expected a pattern or a list of patterns")
+			}
+			patterns = append(patterns, str)
+		}
+	default:
+		return "", fmt.Errorf("expected a pattern or a list of patterns")
+	}
+	for i, p := range patterns {
+		interpolated, err := applyVariablesToString(p, variables)
+		if err != nil {
+			return "", err
+		}
+		patterns[i] = interpolated
+	}
+	return strings.Join(patterns, ","), nil
+}
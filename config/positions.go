@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/compiler"
+)
+
+// sourcePositions maps dot separated paths of definitions, like `host.a.alias` or `config.b.user`,
+// to their locations in a source file
+type sourcePositions struct {
+	filename string
+	paths    map[string]compiler.Position
+}
+
+func newSourcePositions(filename string) sourcePositions {
+	return sourcePositions{
+		filename: filename,
+		paths:    map[string]compiler.Position{},
+	}
+}
+
+func (p sourcePositions) add(pos compiler.Position, path ...string) {
+	if pos.IsValid() {
+		p.paths[strings.Join(path, ".")] = pos
+	}
+}
+
+// of returns the position of a definition, or of the closest enclosing definition with a known position
+func (p sourcePositions) of(path ...string) compiler.Position {
+	for i := len(path); i > 0; i-- {
+		if pos, ok := p.paths[strings.Join(path[:i], ".")]; ok {
+			return pos
+		}
+	}
+	return compiler.Position{Filename: p.filename}
+}
+
+// wrapError prefixes messages of err, keeping their positions if known, or positioning them at pos otherwise
+func wrapError(err error, pos compiler.Position, format string, args ...interface{}) error {
+	prefix := fmt.Sprintf(format, args...)
+	var diagnostics compiler.Diagnostics
+	if errors.As(err, &diagnostics) {
+		wrapped := make(compiler.Diagnostics, 0, len(diagnostics))
+		for _, d := range diagnostics {
+			if !d.Pos.IsValid() {
+				d.Pos = pos
+			}
+			d.Msg = prefix + ": " + d.Msg
+			wrapped = append(wrapped, d)
+		}
+		return wrapped
+	}
+	msg := err.Error()
+	var positioned *compiler.Error
+	if errors.As(err, &positioned) {
+		msg = positioned.Msg
+		if positioned.Pos.IsValid() {
+			pos = positioned.Pos
+		}
+	}
+	return compiler.Errorf(pos, "%s: %s", prefix, msg)
+}
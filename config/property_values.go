@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/keywords"
+)
+
+// forwardFields are fields of forward specifications declared as objects
+var forwardFields = []string{"bind_address", "bind", "host", "port"}
+
+// durationRegexp matches time intervals of ssh_config, numbers followed by optional units, seconds by default
+var durationRegexp = regexp.MustCompile(`^(\d+[sSmMhHdDwW]?)+$`)
+
+// propertyValue applies variables to a config property value and normalizes it according to the type
+// of its keyword. A list of values is kept as a list, so the keyword can be repeated for each of them,
+// unless the keyword takes a comma or space separated list. Forward specifications declared as objects
+// are converted into ssh config syntax.
+func propertyValue(key string, value interface{}, variables variablesMap) (interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			if _, ok := e.([]interface{}); ok {
+				return nil, fmt.Errorf("nested lists are not supported")
+			}
+			single, err := propertyValue(key, e, variables)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, single)
+		}
+		keyword, _ := keywords.Lookup(key)
+		switch keyword.Type {
+		case keywords.List:
+			return joinedList(values, ","), nil
+		case keywords.SpaceList:
+			return joinedList(values, " "), nil
+		}
+		return values, nil
+	case []map[string]interface{}:
+		if len(v) != 1 {
+			values := make([]interface{}, 0, len(v))
+			for _, e := range v {
+				values = append(values, e)
+			}
+			return propertyValue(key, values, variables)
+		}
+		return forwardSpecification(key, v[0], variables)
+	case map[string]interface{}:
+		return forwardSpecification(key, v, variables)
+	case string:
+		interpolated, err := applyVariablesToString(v, variables)
+		if err != nil {
+			return nil, err
+		}
+		return typedValue(key, interpolated)
+	}
+	return typedValue(key, value)
+}
+
+// typedValue converts a single value into the form expected by the keyword, booleans become `yes` or `no`
+// and whole numbers are kept as integers. Values of keywords missing from the catalog are checked only
+// for the latter conversions.
+func typedValue(key string, value interface{}) (interface{}, error) {
+	keyword, known := keywords.Lookup(key)
+	if f, ok := value.(float64); ok {
+		if f != math.Trunc(f) || math.IsInf(f, 0) {
+			if known && (keyword.Type == keywords.Integer || keyword.Type == keywords.Duration) {
+				return nil, typeMismatch(keyword, value)
+			}
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+		value = int(f)
+	}
+	if !known {
+		if b, ok := value.(bool); ok {
+			return flag(b), nil
+		}
+		return value, nil
+	}
+	switch v := value.(type) {
+	case bool:
+		if keyword.Type != keywords.Flag && keyword.Type != keywords.String {
+			return nil, typeMismatch(keyword, value)
+		}
+		return flag(v), nil
+	case int:
+		if keyword.Type == keywords.Flag {
+			return nil, typeMismatch(keyword, value)
+		}
+	case string:
+		if _, err := strconv.Atoi(v); err != nil && keyword.Type == keywords.Integer {
+			return nil, typeMismatch(keyword, value)
+		}
+		if keyword.Type == keywords.Duration && !durationRegexp.MatchString(v) {
+			return nil, typeMismatch(keyword, value)
+		}
+	}
+	return value, nil
+}
+
+func flag(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// joinedList joins elements of a list into a list separated with the separator
+func joinedList(values []interface{}, separator string) string {
+	elements := make([]string, 0, len(values))
+	for _, v := range values {
+		elements = append(elements, fmt.Sprintf("%v", v))
+	}
+	return strings.Join(elements, separator)
+}
+
+func typeMismatch(keyword keywords.Keyword, value interface{}) error {
+	expected := map[keywords.ValueType]string{
+		keywords.String:      "a single value",
+		keywords.Flag:        "`yes` or `no`",
+		keywords.Integer:     "a whole number",
+		keywords.List:        "a comma separated list or a list of names",
+		keywords.Command:     "a command",
+		keywords.Forward:     "a forward specification",
+		keywords.Duration:    "a time interval, like `30` or `1m30s`",
+		keywords.SpaceList:   "a space separated list or a list of arguments",
+		keywords.Environment: "environment variables",
+	}[keyword.Type]
+	return fmt.Errorf("`%s` expects %s, got `%v`", keyword.Name, expected, value)
+}
+
+// forwardSpecification converts an object into `[bind_address:]port [host:hostport]` forward specification
+func forwardSpecification(key string, fields map[string]interface{}, variables variablesMap) (string, error) {
+	known, ok := keywords.Lookup(key)
+	if !ok || known.Type != keywords.Forward {
+		return "", fmt.Errorf("objects are supported only as forward specifications of " +
+			"`local_forward`, `remote_forward` and `dynamic_forward`")
+	}
+	values := map[string]string{}
+	for _, k := range sortedPropertyKeys(fields) {
+		if !contains(forwardFields, k) {
+			return "", fmt.Errorf("unsupported forward specification field `%s`, expected `%s`",
+				k, strings.Join(forwardFields, "`, `"))
+		}
+		var value string
+		switch v := fields[k].(type) {
+		case string:
+			interpolated, err := applyVariablesToString(v, variables)
+			if err != nil {
+				return "", err
+			}
+			value = interpolated
+		case int, int64, float64:
+			value = fmt.Sprintf("%v", v)
+		default:
+			return "", fmt.Errorf("invalid value of forward specification field `%s`: `%v`", k, v)
+		}
+		if value == "" {
+			return "", fmt.Errorf("forward specification field `%s` is empty", k)
+		}
+		values[k] = value
+	}
+	if values["bind"] == "" {
+		return "", fmt.Errorf("forward specification requires `bind` field")
+	}
+	spec := values["bind"]
+	if address := values["bind_address"]; address != "" {
+		spec = net.JoinHostPort(address, spec)
+	}
+	host, port := values["host"], values["port"]
+	switch {
+	case known.Name == "DynamicForward" && (host != "" || port != ""):
+		return "", fmt.Errorf("forward specification of `dynamic_forward` does not take `host` nor `port` fields")
+	case (host == "") != (port == ""):
+		return "", fmt.Errorf("forward specification requires both `host` and `port` fields")
+	case known.Name == "LocalForward" && host == "":
+		return "", fmt.Errorf("forward specification of `local_forward` requires `host` and `port` fields")
+	case host != "":
+		spec += " " + net.JoinHostPort(host, port)
+	}
+	return spec, nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldConvertPropertyValues(t *testing.T) {
+	t.Parallel()
+
+	// given
+	variables := variablesMap{"port": "80"}
+	entries := []struct {
+		key      string
+		value    interface{}
+		expected interface{}
+	}{
+		{"user", "ubuntu", "ubuntu"},
+		{"port", 22, 22},
+		{"send_env", []interface{}{"LANG", "LC_*"}, []interface{}{"LANG", "LC_*"}},
+		{"local_forward", []map[string]interface{}{{"bind": 8080, "host": "localhost", "port": "${port}"}},
+			"8080 localhost:80"},
+		{"local_forward", []interface{}{
+			map[string]interface{}{"bind": 8080, "host": "localhost", "port": 80},
+			[]map[string]interface{}{{"bind_address": "::1", "bind": 8443, "host": "fe80::1", "port": 443}},
+		}, []interface{}{"8080 localhost:80", "[::1]:8443 [fe80::1]:443"}},
+		{"RemoteForward", map[string]interface{}{"bind": 9000}, "9000"},
+		{"dynamic_forward", map[string]interface{}{"bind_address": "localhost", "bind": 1080}, "localhost:1080"},
+		{"forward_agent", true, "yes"},
+		{"compression", false, "no"},
+		{"control_persist", true, "yes"},
+		{"use_keychain", true, "yes"},
+		{"port", float64(2222), 2222},
+		{"port", "${port}", "80"},
+		{"custom_ratio", 0.5, "0.5"},
+		{"custom_limit", float64(1000000), 1000000},
+		{"ciphers", []interface{}{"aes256-gcm@openssh.com", "chacha20-poly1305@openssh.com"},
+			"aes256-gcm@openssh.com,chacha20-poly1305@openssh.com"},
+		{"MACs", "hmac-sha2-512,hmac-sha2-256", "hmac-sha2-512,hmac-sha2-256"},
+		{"user_known_hosts_file", []interface{}{"~/.ssh/known_hosts", "~/.ssh/known_hosts2"},
+			"~/.ssh/known_hosts ~/.ssh/known_hosts2"},
+		{"canonical_domains", []interface{}{"example.com", "example.org"}, "example.com example.org"},
+		{"GlobalKnownHostsFile", "/etc/ssh/ssh_known_hosts /etc/ssh/ssh_known_hosts2",
+			"/etc/ssh/ssh_known_hosts /etc/ssh/ssh_known_hosts2"},
+		{"connect_timeout", "1m", "1m"},
+		{"connect_timeout", 30, 30},
+		{"server_alive_interval", "1h30m", "1h30m"},
+		{"ServerAliveInterval", "90", "90"},
+		{"forward_x11_timeout", "2W", "2W"},
+	}
+
+	for _, e := range entries {
+		// when
+		actual, err := propertyValue(e.key, e.value, variables)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, e.expected, actual)
+	}
+}
+
+func TestShouldRejectInvalidPropertyValues(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		key      string
+		value    interface{}
+		expected string
+	}{
+		{"send_env", []interface{}{[]interface{}{"LANG"}}, "nested lists are not supported"},
+		{"user", map[string]interface{}{"bind": 1}, "objects are supported only as forward specifications of " +
+			"`local_forward`, `remote_forward` and `dynamic_forward`"},
+		{"local_forward", map[string]interface{}{"bind": 1, "to": "x"},
+			"unsupported forward specification field `to`, expected `bind_address`, `bind`, `host`, `port`"},
+		{"local_forward", map[string]interface{}{"host": "localhost", "port": 80},
+			"forward specification requires `bind` field"},
+		{"local_forward", map[string]interface{}{"bind": 8080}, "forward specification of `local_forward` " +
+			"requires `host` and `port` fields"},
+		{"remote_forward", map[string]interface{}{"bind": 8080, "host": "localhost"},
+			"forward specification requires both `host` and `port` fields"},
+		{"dynamic_forward", map[string]interface{}{"bind": 1080, "host": "localhost", "port": 80},
+			"forward specification of `dynamic_forward` does not take `host` nor `port` fields"},
+		{"local_forward", map[string]interface{}{"bind": 8080, "host": "${missing}", "port": 80},
+			"variable `missing` not defined"},
+		{"port", "ssh", "`Port` expects a whole number, got `ssh`"},
+		{"port", true, "`Port` expects a whole number, got `true`"},
+		{"connect_timeout", 1.5, "`ConnectTimeout` expects a time interval, like `30` or `1m30s`, got `1.5`"},
+		{"connect_timeout", "1 minute", "`ConnectTimeout` expects a time interval, like `30` or `1m30s`, got `1 minute`"},
+		{"server_alive_interval", "m", "`ServerAliveInterval` expects a time interval, like `30` or `1m30s`, got `m`"},
+		{"server_alive_interval", true, "`ServerAliveInterval` expects a time interval, like `30` or `1m30s`, got `true`"},
+		{"connection_attempts", 1.5, "`ConnectionAttempts` expects a whole number, got `1.5`"},
+		{"compression", 1, "`Compression` expects `yes` or `no`, got `1`"},
+		{"ciphers", []interface{}{"aes256-ctr", true},
+			"`Ciphers` expects a comma separated list or a list of names, got `true`"},
+		{"proxy_command", false, "`ProxyCommand` expects a command, got `false`"},
+	}
+
+	for _, e := range entries {
+		// when
+		_, err := propertyValue(e.key, e.value, variablesMap{})
+
+		// then
+		assert.EqualError(t, err, e.expected)
+	}
+}
@@ -2,63 +2,124 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"strings"
-
-	"github.com/pkg/errors"
 
 	"github.com/dankraw/ssh-aliases/compiler"
 )
 
 // Reader is able to read directories and files and return inputs for ssh-aliases compiler
 type Reader struct {
-	decoder *decoder
-	scanner *Scanner
+	decoder  *decoder
+	scanner  *Scanner
+	verbose  io.Writer
+	failFast bool
+	// keepPropertyOrder keeps config properties in order of their declaration
+	keepPropertyOrder bool
+	keywords          KeywordOptions
+}
+
+// ReaderOptions customize the way Reader selects and processes input files
+type ReaderOptions struct {
+	Scan ScanOptions
+	// Verbose receives additional information about processed definitions, like overridden hosts or configs
+	Verbose io.Writer
+	// FailFast stops processing at the first error, instead of reporting all errors found in input files
+	FailFast bool
+	// KeepPropertyOrder keeps config properties in order of their declaration, instead of sorting them alphabetically
+	KeepPropertyOrder bool
+	// Keywords tell how keywords of config properties are checked, they are not checked by default
+	Keywords KeywordOptions
 }
 
 // NewReader returns new instance of Reader
 func NewReader() *Reader {
+	return NewReaderWithOptions(ReaderOptions{})
+}
+
+// NewReaderWithOptions returns new instance of Reader configured with provided options
+func NewReaderWithOptions(options ReaderOptions) *Reader {
 	return &Reader{
-		decoder: newDecoder(),
-		scanner: NewScanner(),
+		decoder:  newDecoder(),
+		scanner:  NewScannerWithOptions(options.Scan),
+		verbose:  options.Verbose,
+		failFast: options.FailFast,
+
+		keepPropertyOrder: options.KeepPropertyOrder,
+		keywords:          options.Keywords,
 	}
 }
 
-// ReadConfigs processes the input directory and returns inputs for ssh-aliases compiler
-func (e *Reader) ReadConfigs(dir string) (compiler.InputContext, error) {
-	files, err := e.scanner.ScanDirectory(dir)
-	if err != nil {
+// ReadConfigs processes the input directories and returns inputs for ssh-aliases compiler.
+// Each directory is a separate layer, host and config definitions from later layers
+// override definitions with the same names from earlier layers.
+// Returned error lists all problems found in input files, or only the first one when failing fast.
+func (e *Reader) ReadConfigs(dirs ...string) (compiler.InputContext, error) {
+	ctx, diagnostics := e.CollectConfigs(dirs...)
+	if err := diagnostics.Err(); err != nil {
 		return compiler.InputContext{}, err
 	}
-	var sources = make([]rawContextSource, 0, len(files))
-	for _, f := range files {
-		c, err := e.decodeFile(f)
+	return ctx, nil
+}
+
+// CollectConfigs works like ReadConfigs, but instead of an error it returns the collector of diagnostics,
+// so that problems found later, like compilation errors, can be reported together.
+// Returned context contains only the definitions that were read without errors.
+func (e *Reader) CollectConfigs(dirs ...string) (compiler.InputContext, *compiler.Collector) {
+	diagnostics := compiler.NewCollector(e.failFast)
+	var sources []rawContextSource
+	for layer, dir := range dirs {
+		files, err := e.scanner.ScanDirectory(dir)
 		if err != nil {
-			return compiler.InputContext{}, errors.Wrap(err, fmt.Sprintf("failed parsing `%s`", f))
-		}
-		if len(c.Hosts) < 1 && len(c.RawConfigs) < 1 && len(c.Variables) < 1 {
+			if diagnostics.Add(err) {
+				return compiler.InputContext{}, diagnostics
+			}
 			continue
 		}
-		rawSource := rawContextSource{
-			SourceName: f,
-			RawContext: c,
+		for _, f := range files {
+			c, err := e.decodeFile(f)
+			if err != nil {
+				if diagnostics.Add(wrapError(err, compiler.Position{Filename: f}, "failed parsing")) {
+					return compiler.InputContext{}, diagnostics
+				}
+				continue
+			}
+			if len(c.Hosts) < 1 && len(c.Matches) < 1 && len(c.RawConfigs) < 1 && len(c.Variables) < 1 {
+				continue
+			}
+			rawSource := rawContextSource{
+				SourceName: f,
+				Layer:      layer,
+				RawContext: c,
+			}
+			sources = append(sources, rawSource)
 		}
-		sources = append(sources, rawSource)
 	}
-	return compilerInputContext(sources)
+	sources, overrides := resolveLayers(sources, diagnostics)
+	if diagnostics.Stopped() {
+		return compiler.InputContext{}, diagnostics
+	}
+	if e.verbose != nil {
+		for _, o := range overrides {
+			if _, err := fmt.Fprintln(e.verbose, o.String()); err != nil {
+				diagnostics.Add(err)
+				return compiler.InputContext{}, diagnostics
+			}
+		}
+	}
+	checkKeywords(sources, e.keywords, diagnostics)
+	if diagnostics.Stopped() {
+		return compiler.InputContext{}, diagnostics
+	}
+	return compilerInputContext(sources, e.keepPropertyOrder, diagnostics), diagnostics
 }
 
 func (e *Reader) decodeFile(file string) (rawFileContext, error) {
-	// Basic path validation
-	if strings.Contains(file, "..") || strings.HasPrefix(file, "/") {
-		return rawFileContext{}, fmt.Errorf("invalid file path: %s", file)
-	}
-	
 	data, err := os.ReadFile(file)
 	if err != nil {
 		return rawFileContext{}, err
 	}
-	c, err := e.decoder.decode(data)
+	c, err := e.decoder.decode(file, data)
 	if err != nil {
 		return rawFileContext{}, err
 	}
@@ -1,33 +1,129 @@
 package config
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// IgnoreFileName is the name of a file placed in the scanned directory that lists patterns of ignored paths
+const IgnoreFileName = ".sshaliasesignore"
+
+// ScanOptions tell Scanner which files should be selected
+type ScanOptions struct {
+	// Recursive enables scanning of child directories
+	Recursive bool
+	// Include patterns select files (relative to scanned directory) that should be read, all are read when empty
+	Include []string
+	// Exclude patterns select files and directories (relative to scanned directory) that should be skipped
+	Exclude []string
+}
+
 // Scanner is used to select files that contain ssh-aliases configs
-type Scanner struct{}
+type Scanner struct {
+	options ScanOptions
+	decoder *decoder
+}
 
 // NewScanner creates new instance of Scanner
 func NewScanner() *Scanner {
-	return &Scanner{}
+	return NewScannerWithOptions(ScanOptions{})
 }
 
-const hclExtension = ".hcl"
+// NewScannerWithOptions creates new instance of Scanner that selects files according to provided options
+func NewScannerWithOptions(options ScanOptions) *Scanner {
+	return &Scanner{
+		options: options,
+		decoder: newDecoder(),
+	}
+}
 
 // ScanDirectory returns an array of file names that contain ssh-aliases configs
 func (s *Scanner) ScanDirectory(path string) ([]string, error) {
-	files, err := os.ReadDir(path)
+	include, err := parseGlobPatterns(s.options.Include)
+	if err != nil {
+		return nil, fmt.Errorf("error while scanning `%s`: %s", path, err.Error())
+	}
+	exclude, err := parseGlobPatterns(s.options.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("error while scanning `%s`: %s", path, err.Error())
+	}
+	ignored, err := readIgnoreFile(filepath.Join(path, IgnoreFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error while scanning `%s`: %s", path, err.Error())
+	}
+	exclude = append(exclude, ignored...)
+
+	// symlinked directories are resolved first, as walking does not follow links
+	root, err := filepath.EvalSymlinks(path)
 	if err != nil {
 		return nil, fmt.Errorf("error while scanning `%s`: %s", path, err.Error())
 	}
 	var hcls []string
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), hclExtension) {
-			hcls = append(hcls, filepath.Join(path, file.Name()))
+	err = filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if file == root {
+			if !entry.IsDir() {
+				return errors.New("not a directory")
+			}
+			return nil
 		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() {
+			if !s.options.Recursive || matchesAny(exclude, rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !s.decoder.supports(entry.Name()) || matchesAny(exclude, rel, false) {
+			return nil
+		}
+		if len(include) > 0 && !matchesAny(include, rel, false) {
+			return nil
+		}
+		hcls = append(hcls, filepath.Join(path, filepath.FromSlash(rel)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while scanning `%s`: %s", path, err.Error())
 	}
 	return hcls, nil
 }
+
+func readIgnoreFile(file string) ([]globPattern, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var patterns []string
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err = lines.Err(); err != nil {
+		return nil, err
+	}
+	parsed, err := parseGlobPatterns(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `%s`: %s", IgnoreFileName, err.Error())
+	}
+	return parsed, nil
+}
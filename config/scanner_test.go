@@ -23,3 +23,84 @@ func TestShouldScanDir(t *testing.T) {
 		"../config_test/test_fixtures/valid/basic_with_variables/variables.hcl",
 	}, files)
 }
+
+func TestShouldNotScanChildDirsByDefault(t *testing.T) {
+	t.Parallel()
+
+	// given
+	scanner := NewScanner()
+
+	// when
+	files, err := scanner.ScanDirectory("../config_test/test_fixtures/valid/recursive")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"../config_test/test_fixtures/valid/recursive/common.hcl",
+	}, files)
+}
+
+func TestShouldScanDirRecursivelySkippingIgnoredPaths(t *testing.T) {
+	t.Parallel()
+
+	// given
+	scanner := NewScannerWithOptions(ScanOptions{Recursive: true})
+
+	// when
+	files, err := scanner.ScanDirectory("../config_test/test_fixtures/valid/recursive")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"../config_test/test_fixtures/valid/recursive/common.hcl",
+		"../config_test/test_fixtures/valid/recursive/teams/alpha/prod/alpha.hcl",
+		"../config_test/test_fixtures/valid/recursive/teams/alpha/test/alpha.hcl",
+		"../config_test/test_fixtures/valid/recursive/teams/beta/prod/beta.hcl",
+	}, files)
+}
+
+func TestShouldScanDirRecursivelyWithIncludeAndExcludePatterns(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		options  ScanOptions
+		expected []string
+	}{
+		{ScanOptions{Recursive: true, Include: []string{"teams/**/prod/*.hcl"}}, []string{
+			"../config_test/test_fixtures/valid/recursive/teams/alpha/prod/alpha.hcl",
+			"../config_test/test_fixtures/valid/recursive/teams/beta/prod/beta.hcl",
+		}},
+		{ScanOptions{Recursive: true, Exclude: []string{"test/", "beta"}}, []string{
+			"../config_test/test_fixtures/valid/recursive/common.hcl",
+			"../config_test/test_fixtures/valid/recursive/teams/alpha/prod/alpha.hcl",
+		}},
+		{ScanOptions{Recursive: true, Include: []string{"alpha.hcl"}, Exclude: []string{"/teams/alpha/prod"}}, []string{
+			"../config_test/test_fixtures/valid/recursive/teams/alpha/test/alpha.hcl",
+		}},
+	}
+
+	for _, e := range entries {
+		// when
+		files, err := NewScannerWithOptions(e.options).ScanDirectory("../config_test/test_fixtures/valid/recursive")
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, e.expected, files)
+	}
+}
+
+func TestShouldReturnErrorOnInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	// given
+	scanner := NewScannerWithOptions(ScanOptions{Exclude: []string{"[a-"}})
+
+	// when
+	_, err := scanner.ScanDirectory("../config_test/test_fixtures/valid/recursive")
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, "error while scanning `../config_test/test_fixtures/valid/recursive`: "+
+		"invalid pattern `[a-`: syntax error in pattern", err.Error())
+}
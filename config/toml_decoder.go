@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// decodeTOML does not record positions of definitions, as the decoder does not expose them
+func decodeTOML(fileName string, input []byte) (rawFileContext, error) {
+	var values map[string]interface{}
+	meta, err := toml.Decode(string(input), &values)
+	if err != nil {
+		return rawFileContext{}, err
+	}
+	order := map[string]int{}
+	for i, k := range meta.Keys() {
+		if _, ok := order[k.String()]; !ok {
+			order[k.String()] = i
+		}
+	}
+	doc, err := tomlDocument(values, nil, order)
+	if err != nil {
+		return rawFileContext{}, err
+	}
+	return documentContext(fileName, doc)
+}
+
+// tomlDocument restores the order of keys from decoding metadata, as TOML tables are decoded into maps
+func tomlDocument(table map[string]interface{}, path []string, order map[string]int) (document, error) {
+	keys := make([]string, 0, len(table))
+	for k := range table {
+		keys = append(keys, k)
+	}
+	position := func(k string) int {
+		if p, ok := order[toml.Key(append(append([]string{}, path...), k)).String()]; ok {
+			return p
+		}
+		return len(order)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if position(keys[i]) != position(keys[j]) {
+			return position(keys[i]) < position(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	doc := make(document, 0, len(keys))
+	for _, k := range keys {
+		value, err := tomlValue(table[k], append(append([]string{}, path...), k), order)
+		if err != nil {
+			return nil, err
+		}
+		doc = append(doc, documentEntry{key: k, value: value})
+	}
+	return doc, nil
+}
+
+func tomlValue(value interface{}, path []string, order map[string]int) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return tomlDocument(v, path, order)
+	case []map[string]interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, table := range v {
+			doc, err := tomlDocument(table, path, order)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, doc)
+		}
+		return values, nil
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			converted, err := tomlValue(e, path, order)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, converted)
+		}
+		return values, nil
+	case int64:
+		return int(v), nil
+	case time.Time:
+		return nil, fmt.Errorf("invalid `%s`: date and time values are not supported", strings.Join(path, "."))
+	}
+	return value, nil
+}
@@ -1,20 +1,28 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
 
-func normalizedVariables(sources []rawContextSource) (variablesMap, error) {
+	"github.com/dankraw/ssh-aliases/compiler"
+)
+
+func normalizedVariables(sources []rawContextSource, diagnostics *compiler.Collector) variablesMap {
 	variables := variablesMap{}
 	for _, s := range sources {
 		for k, v := range s.RawContext.Variables {
 			for key, variable := range expandVariable(k, v) {
 				if _, contains := variables[key]; contains {
-					return nil, fmt.Errorf("error in `%s`: variable redeclaration: `%v`", s.SourceName, key)
+					err := compiler.Errorf(s.RawContext.positions.of("var", key), "variable redeclaration: `%v`", key)
+					if diagnostics.Add(err) {
+						return nil
+					}
+					continue
 				}
 				variables[key] = variable
 			}
 		}
 	}
-	return variables, nil
+	return variables
 }
 
 func expandVariable(key string, variable interface{}) map[string]string {
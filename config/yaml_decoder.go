@@ -0,0 +1,71 @@
+package config
+
+import (
+	"github.com/dankraw/ssh-aliases/compiler"
+	"gopkg.in/yaml.v3"
+)
+
+func decodeYAML(fileName string, input []byte) (rawFileContext, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(input, &root); err != nil {
+		return rawFileContext{}, err
+	}
+	if len(root.Content) == 0 {
+		return rawFileContext{}, nil
+	}
+	value, err := yamlNodeValue(fileName, root.Content[0])
+	if err != nil {
+		return rawFileContext{}, err
+	}
+	doc, err := documentValue(value, fileName, compiler.Position{})
+	if err != nil {
+		return rawFileContext{}, err
+	}
+	return documentContext(fileName, doc)
+}
+
+func yamlNodeValue(fileName string, node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		doc := make(document, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, valueNode := node.Content[i], node.Content[i+1]
+			if err := doc.duplicateKey(key.Value, yamlPosition(fileName, key)); err != nil {
+				return nil, err
+			}
+			value, err := yamlNodeValue(fileName, valueNode)
+			if err != nil {
+				return nil, err
+			}
+			pos := yamlPosition(fileName, valueNode)
+			if valueNode.Kind != yaml.ScalarNode {
+				pos = yamlPosition(fileName, key)
+			}
+			doc = append(doc, documentEntry{key.Value, value, pos})
+		}
+		return doc, nil
+	case yaml.SequenceNode:
+		values := make([]interface{}, 0, len(node.Content))
+		for _, n := range node.Content {
+			value, err := yamlNodeValue(fileName, n)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case yaml.AliasNode:
+		return yamlNodeValue(fileName, node.Alias)
+	case yaml.ScalarNode:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	return nil, compiler.Errorf(yamlPosition(fileName, node), "unsupported YAML node")
+}
+
+func yamlPosition(fileName string, node *yaml.Node) compiler.Position {
+	return compiler.Position{Filename: fileName, Line: node.Line, Column: node.Column}
+}
@@ -4,7 +4,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/dankraw/ssh-aliases/compiler"
 	"github.com/dankraw/ssh-aliases/config"
+	"github.com/dankraw/ssh-aliases/keywords"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,27 +18,40 @@ var tests = []struct {
 	dir              string
 	expectedErrorMsg string
 }{
-	{"duplicate_alias", "duplicate host `service-a`"},
-	{"config_not_found", "error in `test_fixtures/invalid/config_not_found/host_only.hcl`: " +
+	{"duplicate_alias", "test_fixtures/invalid/duplicate_alias/second_alias.hcl:1:1: duplicate host `service-a`"},
+	{"duplicate_config", "test_fixtures/invalid/duplicate_config/second_config.hcl:1:1: duplicate config `defaults`"},
+	{"config_not_found", "test_fixtures/invalid/config_not_found/host_only.hcl:4:12: " +
 		"error in `wally-host` host definition: no config `wally` found"},
-	{"invalid_hcl", "failed parsing `test_fixtures/invalid/invalid_hcl/invalid.hcl`: " +
-		"At 7:2: object expected closing RBRACE got: EOF"},
-	{"variable_redeclaration", "error in `test_fixtures/invalid/variable_redeclaration/example.hcl`: " +
+	{"invalid_hcl", "test_fixtures/invalid/invalid_hcl/invalid.hcl:7:2: " +
+		"failed parsing: object expected closing RBRACE got: EOF"},
+	{"invalid_hcl2", "test_fixtures/invalid/invalid_hcl2/example.hcl2:5:3: " +
+		"failed parsing: Unsupported argument; An argument named \"port\" is not expected here."},
+	{"invalid_hcl2_syntax_in_hcl", "test_fixtures/invalid/invalid_hcl2_syntax_in_hcl/example.hcl:12:3: " +
+		"failed parsing: Unsupported argument; An argument named \"port\" is not expected here."},
+	{"variable_redeclaration", "test_fixtures/invalid/variable_redeclaration/example.hcl:5:15: " +
 		"variable redeclaration: `abc.def`"},
-	{"invalid_import_value", "error in `test_fixtures/invalid/invalid_import_value/example.hcl`: " +
+	{"invalid_import_value", "test_fixtures/invalid/invalid_import_value/example.hcl:8:13: " +
 		"invalid `def_conf` config definition: config import statement has invalid value: `1`"},
-	{"alias_and_hostname_not_specified", "error in `test_fixtures/invalid/alias_and_hostname_not_specified/example.hcl`: " +
+	{"alias_and_hostname_not_specified", "test_fixtures/invalid/alias_and_hostname_not_specified/example.hcl:1:1: " +
 		"invalid `wat` host definition: alias and hostname are both empty or undefined"},
-	{"no_hostname_nor_config", "error in `test_fixtures/invalid/no_hostname_nor_config/example.hcl`: " +
+	{"no_hostname_nor_config", "test_fixtures/invalid/no_hostname_nor_config/example.hcl:1:1: " +
 		"no config nor hostname specified for host `wat`"},
-	{"non_existing_variable/in_alias", "error in `test_fixtures/invalid/non_existing_variable/in_alias/example.hcl`: " +
+	{"non_existing_variable/in_alias", "test_fixtures/invalid/non_existing_variable/in_alias/example.hcl:3:11: " +
 		"error in alias of `service-a` host definition: variable `b.c3.d4` not defined"},
-	{"non_existing_variable/in_hostname", "error in `test_fixtures/invalid/non_existing_variable/in_hostname/example.hcl`: " +
+	{"non_existing_variable/in_hostname", "test_fixtures/invalid/non_existing_variable/in_hostname/example.hcl:2:14: " +
 		"error in hostname of `service-a` host definition: variable `b.c3ę.d4.E_F-d` not defined"},
-	{"non_existing_variable/in_config", "error in `test_fixtures/invalid/non_existing_variable/in_config/example.hcl`: " +
+	{"non_existing_variable/in_config", "test_fixtures/invalid/non_existing_variable/in_config/example.hcl:5:12: " +
 		"error in `service-a` host definition: could not compile config property `user`: variable `b.c3.d4` not defined"},
-	{"non_existing_variable/in_external_config", "error in `test_fixtures/invalid/non_existing_variable/in_external_config/example.hcl`: " +
+	{"non_existing_variable/in_external_config", "test_fixtures/invalid/non_existing_variable/in_external_config/example.hcl:8:10: " +
 		"invalid `ext` config definition: could not compile config property `user`: variable `b.c3.d4` not defined"},
+	{"invalid_match/no_criteria", "test_fixtures/invalid/invalid_match/no_criteria/example.hcl:1:1: " +
+		"invalid `nothing` match definition: no criteria specified"},
+	{"invalid_match/all_combined", "test_fixtures/invalid/invalid_match/all_combined/example.hcl:1:1: " +
+		"invalid `everything` match definition: `all` criterion can be combined only with `canonical`"},
+	{"invalid_match/invalid_position", "test_fixtures/invalid/invalid_match/invalid_position/example.hcl:3:14: " +
+		"invalid `middle` match definition: position `middle` is not one of `top` or `bottom`"},
+	{"type_mismatch", "test_fixtures/invalid/type_mismatch/example.hcl:4:12: error in `web` host definition: " +
+		"could not compile config property `port`: `Port` expects a whole number, got `ssh`"},
 }
 
 func TestShouldThrowErrorOnDuplicateAlias(t *testing.T) {
@@ -65,3 +80,82 @@ func TestShouldThrowErrorOnCircularImports(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "circular import in configs")
 }
+
+func TestShouldReportAllErrorsAtOnce(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, err := reader.ReadConfigs(filepath.Join(testsParentDir, "multiple_errors"))
+
+	// then
+	var diagnostics compiler.Diagnostics
+	assert.ErrorAs(t, err, &diagnostics)
+	assert.Equal(t, 5, diagnostics.Count(compiler.SeverityError))
+	assert.Equal(t, "test_fixtures/invalid/multiple_errors/configs.hcl:2:13: invalid `defaults` config definition: "+
+		"trying to import `base`, but such config does not exist\n"+
+		"test_fixtures/invalid/multiple_errors/configs.hcl:3:10: invalid `defaults` config definition: "+
+		"could not compile config property `user`: variable `users.default` not defined\n"+
+		"test_fixtures/invalid/multiple_errors/hosts.hcl:4:12: error in `service-a` host definition: no config `missing` found\n"+
+		"test_fixtures/invalid/multiple_errors/hosts.hcl:11:12: error in `service-b` host definition: "+
+		"could not compile config property `user`: variable `users.b` not defined\n"+
+		"test_fixtures/invalid/multiple_errors/hosts.hcl:12:12: error in `service-b` host definition: "+
+		"could not compile config property `port`: variable `ports.b` not defined", diagnostics.Sorted().Error())
+}
+
+func TestShouldReportOnlyFirstErrorWhenFailingFast(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReaderWithOptions(config.ReaderOptions{FailFast: true})
+
+	// when
+	_, err := reader.ReadConfigs(filepath.Join(testsParentDir, "multiple_errors"))
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, "test_fixtures/invalid/multiple_errors/configs.hcl:3:10: invalid `defaults` config definition: "+
+		"could not compile config property `user`: variable `users.default` not defined", err.Error())
+}
+
+func TestShouldWarnAboutUnknownAndDeprecatedKeywords(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReaderWithOptions(config.ReaderOptions{
+		Keywords: config.KeywordOptions{Strictness: keywords.Warn},
+	})
+
+	// when
+	_, diagnostics := reader.CollectConfigs(filepath.Join(testsParentDir, "unknown_keywords"))
+
+	// then
+	assert.NoError(t, diagnostics.Err())
+	assert.Equal(t, 4, diagnostics.Diagnostics().Count(compiler.SeverityWarning))
+	assert.Equal(t, "test_fixtures/invalid/unknown_keywords/example.hcl:5:20: warning: unknown keyword `identiy_file` "+
+		"in `web` host definition, did you mean `identity_file`?\n"+
+		"test_fixtures/invalid/unknown_keywords/example.hcl:6:20: warning: unknown keyword `use_keychain` in `web` host definition\n"+
+		"test_fixtures/invalid/unknown_keywords/example.hcl:7:30: warning: keyword `PubkeyAcceptedKeyTypes` in `web` host definition "+
+		"is deprecated since OpenSSH 8.5, use `PubkeyAcceptedAlgorithms` instead\n"+
+		"test_fixtures/invalid/unknown_keywords/example.hcl:12:15: warning: unknown keyword `proxy_jmp` "+
+		"in `defaults` config definition, did you mean `proxy_jump`?", diagnostics.Diagnostics().Sorted().Error())
+}
+
+func TestShouldReportUnknownKeywordsAsErrorsUnlessAllowed(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReaderWithOptions(config.ReaderOptions{
+		Keywords: config.KeywordOptions{Strictness: keywords.Error, Allowed: []string{"UseKeychain", "proxy_jmp"}},
+	})
+
+	// when
+	_, diagnostics := reader.CollectConfigs(filepath.Join(testsParentDir, "unknown_keywords"))
+
+	// then
+	assert.Equal(t, 1, diagnostics.Diagnostics().Count(compiler.SeverityError))
+	assert.Equal(t, 1, diagnostics.Diagnostics().Count(compiler.SeverityWarning))
+	assert.EqualError(t, diagnostics.Err(), "test_fixtures/invalid/unknown_keywords/example.hcl:5:20: "+
+		"unknown keyword `identiy_file` in `web` host definition, did you mean `identity_file`?\n"+
+		"test_fixtures/invalid/unknown_keywords/example.hcl:7:30: warning: keyword `PubkeyAcceptedKeyTypes` in `web` host definition "+
+		"is deprecated since OpenSSH 8.5, use `PubkeyAcceptedAlgorithms` instead")
+}
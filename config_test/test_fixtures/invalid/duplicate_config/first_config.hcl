@@ -0,0 +1,3 @@
+config "defaults" {
+  user = "joe"
+}
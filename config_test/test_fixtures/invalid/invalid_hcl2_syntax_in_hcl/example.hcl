@@ -0,0 +1,13 @@
+var {
+  users {
+    a = "joe"
+  }
+}
+
+host "service-a" {
+  hostname = "service-a.example.com"
+  config {
+    user = users.a
+  }
+  port = 22
+}
@@ -0,0 +1,7 @@
+match "everything" {
+  all = true
+  user = "deploy"
+  config {
+    user = "nobody"
+  }
+}
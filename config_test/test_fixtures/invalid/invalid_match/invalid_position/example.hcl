@@ -0,0 +1,7 @@
+match "middle" {
+  host = "*"
+  position = "middle"
+  config {
+    user = "nobody"
+  }
+}
@@ -0,0 +1,5 @@
+match "nothing" {
+  config {
+    user = "nobody"
+  }
+}
@@ -0,0 +1,8 @@
+config "defaults" {
+  _extend = "base"
+  user = "${users.default}"
+}
+
+var {
+  domain = "example.com"
+}
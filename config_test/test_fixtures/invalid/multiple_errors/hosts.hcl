@@ -0,0 +1,20 @@
+host "service-a" {
+  hostname = "service-a.${domain}"
+  alias = "a"
+  config = "missing"
+}
+
+host "service-b" {
+  hostname = "service-b.example.com"
+  alias = "b"
+  config = {
+    user = "${users.b}"
+    port = "${ports.b}"
+  }
+}
+
+host "service-c" {
+  hostname = "service-c.example.com"
+  alias = "c"
+  config = "defaults"
+}
@@ -0,0 +1,6 @@
+host "web" {
+  hostname = "web.example.com"
+  config {
+    port = "ssh"
+  }
+}
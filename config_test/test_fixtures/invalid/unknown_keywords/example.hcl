@@ -0,0 +1,13 @@
+host "web" {
+  hostname = "web.example.com"
+  alias = "web"
+  config {
+    identiy_file = "~/.ssh/web.pem"
+    use_keychain = "yes"
+    PubkeyAcceptedKeyTypes = "+ssh-rsa"
+  }
+}
+
+config "defaults" {
+  proxy_jmp = "jump"
+}
@@ -0,0 +1,4 @@
+host "bastion" {
+  hostname = "bastion.${domain}"
+  alias = "jump"
+}
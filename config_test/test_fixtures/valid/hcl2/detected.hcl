@@ -0,0 +1,9 @@
+# HCL2 syntax is detected automatically in .hcl files
+host "service-b" {
+  hostname = "service-b.${domain}"
+  alias    = "b"
+  config = {
+    forward_agent         = !false
+    server_alive_interval = 60 * 2
+  }
+}
@@ -0,0 +1,10 @@
+host "db" {
+  hostname = "db.example.com"
+  alias = "database"
+  config = "defaults"
+}
+
+config "defaults" {
+  user = "me"
+  port = 22
+}
@@ -0,0 +1,16 @@
+host "app" {
+  hostname = "app[1..2].example.com"
+  alias = "app{#1}"
+  config = "defaults"
+}
+
+host "db" {
+  hostname = "db.example.com"
+  alias = "db"
+  config = "defaults"
+}
+
+config "defaults" {
+  user = "deploy"
+  port = 22
+}
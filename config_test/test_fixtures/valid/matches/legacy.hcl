@@ -0,0 +1,9 @@
+match "production" {
+  host = ["*.prod.${domain}", "prod-*"]
+  user = "deploy"
+  config = "deployment"
+}
+
+config "deployment" {
+  identity_file = "~/.ssh/deploy.pem"
+}
@@ -0,0 +1,15 @@
+host "defaults" {
+  alias = "*"
+  priority = -1
+  config {
+    user = "ubuntu"
+    forward_agent = "no"
+    _extend = "base"
+    identity_file = "~/.ssh/id_ed25519"
+  }
+}
+
+config "base" {
+  server_alive_interval = 60
+  compression = "yes"
+}
@@ -0,0 +1,3 @@
+config "team-defaults" {
+  user = "deploy"
+}
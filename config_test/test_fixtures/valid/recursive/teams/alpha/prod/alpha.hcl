@@ -0,0 +1,5 @@
+host "alpha-prod" {
+  hostname = "alpha[1..2].prod.example.com"
+  alias = "alpha-prod{#1}"
+  config = "team-defaults"
+}
@@ -0,0 +1,5 @@
+host "alpha-test" {
+  hostname = "alpha[1..2].test.example.com"
+  alias = "alpha-test{#1}"
+  config = "team-defaults"
+}
@@ -0,0 +1,5 @@
+host "beta-prod" {
+  hostname = "beta[1..2].prod.example.com"
+  alias = "beta-prod{#1}"
+  config = "team-defaults"
+}
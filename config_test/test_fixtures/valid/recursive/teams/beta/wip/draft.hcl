@@ -0,0 +1,4 @@
+host "beta-draft" {
+  hostname = "beta-draft.example.com"
+  config = "not-ready-yet"
+}
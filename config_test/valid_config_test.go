@@ -1,6 +1,8 @@
 package config
 
 import (
+	"bytes"
+	"path/filepath"
 	"testing"
 
 	"github.com/dankraw/ssh-aliases/compiler"
@@ -31,11 +33,11 @@ func TestShouldReadCompleteConfigFromDir(t *testing.T) {
 						compiler.ConfigProperty{
 							Key:   "IdentityFile",
 							Value: "a_1001_id_secret_rsa.pem",
-						}, 
+						},
 						compiler.ConfigProperty{
 							Key:   "Port",
 							Value: 22,
-						}, 
+						},
 						compiler.ConfigProperty{
 							Key:   "User",
 							Value: "deployment",
@@ -49,7 +51,7 @@ func TestShouldReadCompleteConfigFromDir(t *testing.T) {
 						compiler.ConfigProperty{
 							Key:   "IdentityFile",
 							Value: "b_id_1001_rsa.pem",
-						}, 
+						},
 						compiler.ConfigProperty{
 							Key:   "Port",
 							Value: 22,
@@ -61,7 +63,7 @@ func TestShouldReadCompleteConfigFromDir(t *testing.T) {
 				Hosts:      []compiler.ExpandingHostConfig{},
 			},
 		},
-	}, ctx)
+	}, withoutPositions(ctx))
 }
 
 func TestShouldReadFilesWithImportedConfigs(t *testing.T) {
@@ -87,11 +89,11 @@ func TestShouldReadFilesWithImportedConfigs(t *testing.T) {
 						compiler.ConfigProperty{
 							Key:   "Additional",
 							Value: "extension",
-						}, 
+						},
 						compiler.ConfigProperty{
 							Key:   "Another",
 							Value: "one",
-						}, 
+						},
 						compiler.ConfigProperty{
 							Key:   "X",
 							Value: "y",
@@ -105,15 +107,15 @@ func TestShouldReadFilesWithImportedConfigs(t *testing.T) {
 						compiler.ConfigProperty{
 							Key:   "Additional",
 							Value: "extension 2",
-						}, 
+						},
 						compiler.ConfigProperty{
 							Key:   "Another",
 							Value: "two",
-						}, 
+						},
 						compiler.ConfigProperty{
 							Key:   "SomeProp",
 							Value: 123,
-						}, 
+						},
 						compiler.ConfigProperty{
 							Key:   "This",
 							Value: "never happens",
@@ -122,7 +124,7 @@ func TestShouldReadFilesWithImportedConfigs(t *testing.T) {
 				}},
 			},
 		},
-	}, ctx)
+	}, withoutPositions(ctx))
 }
 
 func TestShouldReadHostDefinitionsWithoutHostnames(t *testing.T) {
@@ -161,7 +163,7 @@ func TestShouldReadHostDefinitionsWithoutHostnames(t *testing.T) {
 				}},
 			},
 		},
-	}, ctx)
+	}, withoutPositions(ctx))
 }
 
 func TestShouldReadHostDefinitionsWithoutConfig(t *testing.T) {
@@ -187,5 +189,446 @@ func TestShouldReadHostDefinitionsWithoutConfig(t *testing.T) {
 				}},
 			},
 		},
-	}, ctx)
+	}, withoutPositions(ctx))
+}
+
+func TestShouldReadConfigsFromChildDirs(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReaderWithOptions(config.ReaderOptions{
+		Scan: config.ScanOptions{
+			Recursive: true,
+			Exclude:   []string{"test/"},
+		},
+	})
+	deploy := compiler.ConfigProperties{
+		compiler.ConfigProperty{
+			Key:   "User",
+			Value: "deploy",
+		},
+	}
+
+	// when
+	ctx, err := reader.ReadConfigs("./test_fixtures/valid/recursive")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.InputContext{
+		Sources: []compiler.ContextSource{
+			{
+				SourceName: "test_fixtures/valid/recursive/common.hcl",
+				Hosts:      []compiler.ExpandingHostConfig{},
+			}, {
+				SourceName: "test_fixtures/valid/recursive/teams/alpha/prod/alpha.hcl",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "alpha-prod",
+					HostnamePattern: "alpha[1..2].prod.example.com",
+					AliasTemplate:   "alpha-prod{#1}",
+					Config:          deploy,
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/recursive/teams/beta/prod/beta.hcl",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "beta-prod",
+					HostnamePattern: "beta[1..2].prod.example.com",
+					AliasTemplate:   "beta-prod{#1}",
+					Config:          deploy,
+				}},
+			},
+		},
+	}, withoutPositions(ctx))
+}
+
+func TestShouldOverrideDefinitionsFromEarlierDirs(t *testing.T) {
+	t.Parallel()
+
+	// given
+	verbose := new(bytes.Buffer)
+	reader := config.NewReaderWithOptions(config.ReaderOptions{
+		Verbose: verbose,
+	})
+	personal := compiler.ConfigProperties{
+		compiler.ConfigProperty{
+			Key:   "Port",
+			Value: 22,
+		},
+		compiler.ConfigProperty{
+			Key:   "User",
+			Value: "me",
+		},
+	}
+
+	// when
+	ctx, err := reader.ReadConfigs("./test_fixtures/valid/layers/team", "./test_fixtures/valid/layers/personal")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.InputContext{
+		Sources: []compiler.ContextSource{
+			{
+				SourceName: "test_fixtures/valid/layers/team/services.hcl",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "app",
+					HostnamePattern: "app[1..2].example.com",
+					AliasTemplate:   "app{#1}",
+					Config:          personal,
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/layers/personal/overrides.hcl",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "db",
+					HostnamePattern: "db.example.com",
+					AliasTemplate:   "database",
+					Config:          personal,
+				}},
+			},
+		},
+	}, withoutPositions(ctx))
+	assert.Equal(t, "host `db` from `test_fixtures/valid/layers/team/services.hcl` "+
+		"is overridden by `test_fixtures/valid/layers/personal/overrides.hcl`\n"+
+		"config `defaults` from `test_fixtures/valid/layers/team/services.hcl` "+
+		"is overridden by `test_fixtures/valid/layers/personal/overrides.hcl`\n", verbose.String())
+}
+
+func TestShouldReadLayersFromAbsoluteAndParentRelativeDirs(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReader()
+	team, err := filepath.Abs("./test_fixtures/valid/layers/team")
+	assert.NoError(t, err)
+	personal := "../config_test/test_fixtures/valid/layers/personal"
+
+	// when
+	ctx, err := reader.ReadConfigs(team, personal)
+
+	// then
+	assert.NoError(t, err)
+	var sources, hosts []string
+	for _, s := range ctx.Sources {
+		sources = append(sources, s.SourceName)
+		for _, h := range s.Hosts {
+			hosts = append(hosts, h.AliasName)
+		}
+	}
+	assert.Equal(t, []string{filepath.Join(team, "services.hcl"), "../config_test/test_fixtures/valid/layers/personal/overrides.hcl"}, sources)
+	assert.Equal(t, []string{"app", "db"}, hosts)
+}
+
+func TestShouldNotAllowDuplicatesWithinSingleDir(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReader()
+
+	// when
+	_, err := reader.ReadConfigs("./test_fixtures/valid/layers/team", "./test_fixtures/invalid/duplicate_alias")
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, "test_fixtures/invalid/duplicate_alias/second_alias.hcl:1:1: duplicate host `service-a`", err.Error())
+}
+
+func TestShouldReadHCL2Files(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReader()
+
+	// when
+	ctx, err := reader.ReadConfigs("./test_fixtures/valid/hcl2")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.InputContext{
+		Sources: []compiler.ContextSource{
+			{
+				SourceName: "test_fixtures/valid/hcl2/detected.hcl",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "service-b",
+					HostnamePattern: "service-b.example.com",
+					AliasTemplate:   "b",
+					Config: compiler.ConfigProperties{
+						compiler.ConfigProperty{
+							Key:   "ForwardAgent",
+							Value: "yes",
+						},
+						compiler.ConfigProperty{
+							Key:   "ServerAliveInterval",
+							Value: 120,
+						},
+					},
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/hcl2/example.hcl2",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "service-a",
+					HostnamePattern: "service-a[1..2].example.com",
+					AliasTemplate:   "a{#1}",
+					Config: compiler.ConfigProperties{
+						compiler.ConfigProperty{
+							Key:   "IdentityFile",
+							Value: "~/.ssh/eden.pem",
+						},
+						compiler.ConfigProperty{
+							Key:   "Port",
+							Value: 2222,
+						},
+						compiler.ConfigProperty{
+							Key:   "User",
+							Value: "eden",
+						},
+					},
+				}},
+			},
+		},
+	}, withoutPositions(ctx))
+}
+
+func TestShouldReadMixedFileFormats(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReader()
+	common := compiler.ConfigProperties{
+		compiler.ConfigProperty{
+			Key:   "ForwardAgent",
+			Value: "no",
+		},
+		compiler.ConfigProperty{
+			Key:   "IdentityFile",
+			Value: "~/.ssh/deploy.pem",
+		},
+		compiler.ConfigProperty{
+			Key:   "User",
+			Value: "deployer",
+		},
+	}
+
+	// when
+	ctx, err := reader.ReadConfigs("./test_fixtures/valid/formats")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.InputContext{
+		Sources: []compiler.ContextSource{
+			{
+				SourceName: "test_fixtures/valid/formats/configs.json",
+				Hosts:      []compiler.ExpandingHostConfig{},
+			}, {
+				SourceName: "test_fixtures/valid/formats/hosts.yaml",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "web",
+					HostnamePattern: "web[1..2].example.com",
+					AliasTemplate:   "web{#1}",
+					Config:          common,
+				}, {
+					AliasName:       "db",
+					HostnamePattern: "db.example.com",
+					AliasTemplate:   "db",
+					Config: compiler.ConfigProperties{
+						common[0],
+						common[1],
+						compiler.ConfigProperty{
+							Key:   "Port",
+							Value: 2222,
+						},
+						common[2],
+					},
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/formats/legacy.hcl",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "bastion",
+					HostnamePattern: "bastion.example.com",
+					AliasTemplate:   "jump",
+					Config:          compiler.ConfigProperties{},
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/formats/variables.toml",
+				Hosts:      []compiler.ExpandingHostConfig{},
+			},
+		},
+	}, withoutPositions(ctx))
+}
+
+func TestShouldReadPositionsOfHostDefinitions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReader()
+
+	// when
+	ctx, err := reader.ReadConfigs("./test_fixtures/valid/formats")
+
+	// then
+	assert.NoError(t, err)
+	hosts := ctx.Sources[1].Hosts
+	assert.Equal(t, compiler.Position{Filename: "test_fixtures/valid/formats/hosts.yaml", Line: 6, Column: 3}, hosts[1].Position)
+	assert.Equal(t, compiler.Position{Filename: "test_fixtures/valid/formats/hosts.yaml", Line: 7, Column: 15}, hosts[1].HostnamePosition)
+	assert.Equal(t, compiler.Position{Filename: "test_fixtures/valid/formats/hosts.yaml", Line: 8, Column: 12}, hosts[1].AliasPosition)
+	hosts = ctx.Sources[2].Hosts
+	assert.Equal(t, compiler.Position{Filename: "test_fixtures/valid/formats/legacy.hcl", Line: 1, Column: 1}, hosts[0].Position)
+	assert.Equal(t, compiler.Position{Filename: "test_fixtures/valid/formats/legacy.hcl", Line: 2, Column: 14}, hosts[0].HostnamePosition)
+	assert.Equal(t, compiler.Position{Filename: "test_fixtures/valid/formats/legacy.hcl", Line: 3, Column: 11}, hosts[0].AliasPosition)
+}
+
+func TestShouldReadMatchDefinitions(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReader()
+
+	// when
+	ctx, err := reader.ReadConfigs("./test_fixtures/valid/matches")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.InputContext{
+		Sources: []compiler.ContextSource{
+			{
+				SourceName: "test_fixtures/valid/matches/legacy.hcl",
+				Hosts:      []compiler.ExpandingHostConfig{},
+				Matches: []compiler.MatchConfig{{
+					Name: "production",
+					Criteria: []compiler.MatchCriterion{
+						{Keyword: "host", Value: "*.prod.example.com,prod-*"},
+						{Keyword: "user", Value: "deploy"},
+					},
+					Config: compiler.ConfigProperties{
+						compiler.ConfigProperty{
+							Key:   "IdentityFile",
+							Value: "~/.ssh/deploy.pem",
+						},
+					},
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/matches/matches.hcl2",
+				Hosts:      []compiler.ExpandingHostConfig{},
+				Matches: []compiler.MatchConfig{{
+					Name: "canonical",
+					Criteria: []compiler.MatchCriterion{
+						{Keyword: "canonical"},
+						{Keyword: "all"},
+					},
+					Config: compiler.ConfigProperties{
+						compiler.ConfigProperty{
+							Key:   "ForwardAgent",
+							Value: "no",
+						},
+					},
+					Top: true,
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/matches/matches.yaml",
+				Hosts:      []compiler.ExpandingHostConfig{},
+				Matches: []compiler.MatchConfig{{
+					Name: "jump",
+					Criteria: []compiler.MatchCriterion{
+						{Keyword: "originalhost", Value: "bastion"},
+						{Keyword: "exec", Value: "test -f ~/.ssh/jump"},
+					},
+					Config: compiler.ConfigProperties{
+						compiler.ConfigProperty{
+							Key:   "ProxyJump",
+							Value: "jump.example.com",
+						},
+					},
+				}},
+			},
+		},
+	}, withoutPositions(ctx))
+}
+
+func TestShouldKeepDeclaredPropertyOrder(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReaderWithOptions(config.ReaderOptions{KeepPropertyOrder: true})
+
+	// when
+	ctx, err := reader.ReadConfigs("./test_fixtures/valid/property_order")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.InputContext{
+		Sources: []compiler.ContextSource{
+			{
+				SourceName: "test_fixtures/valid/property_order/hosts.hcl",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:     "defaults",
+					AliasTemplate: "*",
+					Priority:      -1,
+					Config: compiler.ConfigProperties{
+						{Key: "User", Value: "ubuntu"},
+						{Key: "ForwardAgent", Value: "no"},
+						{Key: "IdentityFile", Value: "~/.ssh/id_ed25519"},
+						{Key: "ServerAliveInterval", Value: 60},
+						{Key: "Compression", Value: "yes"},
+					},
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/property_order/hosts.hcl2",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "jump",
+					HostnamePattern: "jump.example.com",
+					AliasTemplate:   "jump",
+					Priority:        10,
+					Config: compiler.ConfigProperties{
+						{Key: "User", Value: "admin"},
+						{Key: "Compression", Value: "no"},
+						{Key: "ServerAliveInterval", Value: 60},
+					},
+				}},
+			}, {
+				SourceName: "test_fixtures/valid/property_order/hosts.yaml",
+				Hosts: []compiler.ExpandingHostConfig{{
+					AliasName:       "web",
+					HostnamePattern: "web.example.com",
+					AliasTemplate:   "web",
+					Priority:        5,
+					Config: compiler.ConfigProperties{
+						{Key: "User", Value: "deploy"},
+						{Key: "Port", Value: 2222},
+					},
+				}},
+			},
+		},
+	}, withoutPositions(ctx))
+}
+
+func TestShouldSortPropertiesAlphabeticallyByDefault(t *testing.T) {
+	t.Parallel()
+
+	// given
+	reader := config.NewReader()
+
+	// when
+	ctx, err := reader.ReadConfigs("./test_fixtures/valid/property_order")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, compiler.ConfigProperties{
+		{Key: "Compression", Value: "yes"},
+		{Key: "ForwardAgent", Value: "no"},
+		{Key: "IdentityFile", Value: "~/.ssh/id_ed25519"},
+		{Key: "ServerAliveInterval", Value: 60},
+		{Key: "User", Value: "ubuntu"},
+	}, ctx.Sources[0].Hosts[0].Config)
+}
+
+// withoutPositions clears positions of host and match definitions, so expected contexts can focus on compiled values
+func withoutPositions(ctx compiler.InputContext) compiler.InputContext {
+	for _, s := range ctx.Sources {
+		for i := range s.Hosts {
+			s.Hosts[i].Position = compiler.Position{}
+			s.Hosts[i].HostnamePosition = compiler.Position{}
+			s.Hosts[i].AliasPosition = compiler.Position{}
+		}
+		for i := range s.Matches {
+			s.Matches[i].Position = compiler.Position{}
+		}
+	}
+	return ctx
 }
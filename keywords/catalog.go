@@ -0,0 +1,243 @@
+// Package keywords provides a catalog of OpenSSH client configuration keywords
+package keywords
+
+import (
+	"strings"
+)
+
+// ValueType describes values that a keyword takes
+type ValueType int
+
+const (
+	// String keywords take a single argument, like a path, an address or one of predefined choices
+	String ValueType = iota
+	// Flag keywords take `yes` or `no`, some of them accept additional choices
+	Flag
+	// Integer keywords take a whole number
+	Integer
+	// List keywords take a comma separated list of algorithms or methods
+	List
+	// Command keywords take the rest of the line as a command passed to the shell
+	Command
+	// Forward keywords take a forward specification and may be repeated
+	Forward
+	// Duration keywords take a time interval, a number of seconds or a sequence like `1h30m`
+	Duration
+	// SpaceList keywords take a space separated list of arguments, like file names or domains
+	SpaceList
+	// Environment keywords take a space separated list of environment variables and may be repeated
+	Environment
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case Flag:
+		return "flag"
+	case Integer:
+		return "integer"
+	case List:
+		return "list"
+	case Command:
+		return "command"
+	case Forward:
+		return "forward"
+	case Duration:
+		return "duration"
+	case SpaceList:
+		return "space separated list"
+	case Environment:
+		return "environment"
+	}
+	return "string"
+}
+
+// Keyword describes a single ssh_config keyword
+type Keyword struct {
+	// Name is the keyword spelled as in ssh_config(5)
+	Name string
+	Type ValueType
+	// Since is the OpenSSH version that introduced the keyword, empty for keywords available in all versions
+	// supported by the catalog
+	Since string
+	// Deprecated is the OpenSSH version that deprecated or removed the keyword
+	Deprecated string
+	// ReplacedBy is the name of a keyword that should be used instead of a deprecated one
+	ReplacedBy string
+}
+
+// catalog lists keywords of OpenSSH client configuration, starting with OpenSSH 5.0
+var catalog = []Keyword{
+	{Name: "AddKeysToAgent", Type: Flag, Since: "7.2"},
+	{Name: "AddressFamily", Type: String},
+	{Name: "BatchMode", Type: Flag},
+	{Name: "BindAddress", Type: String},
+	{Name: "BindInterface", Type: String, Since: "7.8"},
+	{Name: "CanonicalDomains", Type: SpaceList, Since: "6.5"},
+	{Name: "CanonicalizeFallbackLocal", Type: Flag, Since: "6.5"},
+	{Name: "CanonicalizeHostname", Type: Flag, Since: "6.5"},
+	{Name: "CanonicalizeMaxDots", Type: Integer, Since: "6.5"},
+	{Name: "CanonicalizePermittedCNAMEs", Type: SpaceList, Since: "6.5"},
+	{Name: "CASignatureAlgorithms", Type: List, Since: "7.9"},
+	{Name: "CertificateFile", Type: String, Since: "7.2"},
+	{Name: "ChallengeResponseAuthentication", Type: Flag, Deprecated: "8.7", ReplacedBy: "KbdInteractiveAuthentication"},
+	{Name: "ChannelTimeout", Type: SpaceList, Since: "9.5"},
+	{Name: "CheckHostIP", Type: Flag},
+	{Name: "Cipher", Type: String, Deprecated: "7.6", ReplacedBy: "Ciphers"},
+	{Name: "Ciphers", Type: List},
+	{Name: "ClearAllForwardings", Type: Flag},
+	{Name: "Compression", Type: Flag},
+	{Name: "CompressionLevel", Type: Integer, Deprecated: "7.4"},
+	{Name: "ConnectionAttempts", Type: Integer},
+	{Name: "ConnectTimeout", Type: Duration},
+	{Name: "ControlMaster", Type: Flag},
+	{Name: "ControlPath", Type: String},
+	{Name: "ControlPersist", Type: String, Since: "5.6"},
+	{Name: "DynamicForward", Type: Forward},
+	{Name: "EnableEscapeCommandline", Type: Flag, Since: "9.2"},
+	{Name: "EnableSSHKeysign", Type: Flag},
+	{Name: "EscapeChar", Type: String},
+	{Name: "ExitOnForwardFailure", Type: Flag},
+	{Name: "FingerprintHash", Type: String, Since: "6.8"},
+	{Name: "ForkAfterAuthentication", Type: Flag, Since: "8.7"},
+	{Name: "ForwardAgent", Type: Flag},
+	{Name: "ForwardX11", Type: Flag},
+	{Name: "ForwardX11Timeout", Type: Duration, Since: "5.6"},
+	{Name: "ForwardX11Trusted", Type: Flag},
+	{Name: "GatewayPorts", Type: Flag},
+	{Name: "GlobalKnownHostsFile", Type: SpaceList},
+	{Name: "GSSAPIAuthentication", Type: Flag},
+	{Name: "GSSAPIDelegateCredentials", Type: Flag},
+	{Name: "HashKnownHosts", Type: Flag},
+	{Name: "HostbasedAcceptedAlgorithms", Type: List, Since: "8.5"},
+	{Name: "HostbasedAuthentication", Type: Flag},
+	{Name: "HostbasedKeyTypes", Type: List, Since: "5.8", Deprecated: "8.5", ReplacedBy: "HostbasedAcceptedAlgorithms"},
+	{Name: "HostKeyAlgorithms", Type: List},
+	{Name: "HostKeyAlias", Type: String},
+	{Name: "HostName", Type: String},
+	{Name: "IdentitiesOnly", Type: Flag},
+	{Name: "IdentityAgent", Type: String, Since: "7.3"},
+	{Name: "IdentityFile", Type: String},
+	{Name: "IgnoreUnknown", Type: List, Since: "6.3"},
+	{Name: "Include", Type: SpaceList, Since: "7.3"},
+	{Name: "IPQoS", Type: SpaceList, Since: "5.7"},
+	{Name: "KbdInteractiveAuthentication", Type: Flag},
+	{Name: "KbdInteractiveDevices", Type: List},
+	{Name: "KexAlgorithms", Type: List, Since: "5.7"},
+	{Name: "KnownHostsCommand", Type: Command, Since: "8.5"},
+	{Name: "LocalCommand", Type: Command},
+	{Name: "LocalForward", Type: Forward},
+	{Name: "LogLevel", Type: String},
+	{Name: "LogVerbose", Type: List, Since: "8.5"},
+	{Name: "MACs", Type: List},
+	{Name: "NoHostAuthenticationForLocalhost", Type: Flag},
+	{Name: "NumberOfPasswordPrompts", Type: Integer},
+	{Name: "ObscureKeystrokeTiming", Type: String, Since: "9.5"},
+	{Name: "PasswordAuthentication", Type: Flag},
+	{Name: "PermitLocalCommand", Type: Flag},
+	{Name: "PermitRemoteOpen", Type: SpaceList, Since: "8.2"},
+	{Name: "PKCS11Provider", Type: String},
+	{Name: "Port", Type: Integer},
+	{Name: "PreferredAuthentications", Type: List},
+	{Name: "Protocol", Type: String, Deprecated: "7.6"},
+	{Name: "ProxyCommand", Type: Command},
+	{Name: "ProxyJump", Type: String, Since: "7.3"},
+	{Name: "ProxyUseFdpass", Type: Flag, Since: "6.5"},
+	{Name: "PubkeyAcceptedAlgorithms", Type: List, Since: "8.5"},
+	{Name: "PubkeyAcceptedKeyTypes", Type: List, Since: "5.8", Deprecated: "8.5", ReplacedBy: "PubkeyAcceptedAlgorithms"},
+	{Name: "PubkeyAuthentication", Type: Flag},
+	{Name: "RekeyLimit", Type: SpaceList},
+	{Name: "RemoteCommand", Type: Command, Since: "7.6"},
+	{Name: "RemoteForward", Type: Forward},
+	{Name: "RequestTTY", Type: Flag, Since: "5.9"},
+	{Name: "RequiredRSASize", Type: Integer, Since: "9.1"},
+	{Name: "RevokedHostKeys", Type: String, Since: "7.3"},
+	{Name: "RhostsRSAAuthentication", Type: Flag, Deprecated: "7.6"},
+	{Name: "RSAAuthentication", Type: Flag, Deprecated: "7.6"},
+	{Name: "SecurityKeyProvider", Type: String, Since: "8.2"},
+	{Name: "SendEnv", Type: Environment},
+	{Name: "ServerAliveCountMax", Type: Integer},
+	{Name: "ServerAliveInterval", Type: Duration},
+	{Name: "SessionType", Type: String, Since: "8.7"},
+	{Name: "SetEnv", Type: Environment, Since: "7.8"},
+	{Name: "StdinNull", Type: Flag, Since: "8.7"},
+	{Name: "StreamLocalBindMask", Type: String, Since: "6.7"},
+	{Name: "StreamLocalBindUnlink", Type: Flag, Since: "6.7"},
+	{Name: "StrictHostKeyChecking", Type: Flag},
+	{Name: "SyslogFacility", Type: String},
+	{Name: "Tag", Type: String, Since: "9.4"},
+	{Name: "TCPKeepAlive", Type: Flag},
+	{Name: "Tunnel", Type: Flag},
+	{Name: "TunnelDevice", Type: String},
+	{Name: "UpdateHostKeys", Type: Flag, Since: "6.8"},
+	{Name: "UsePrivilegedPort", Type: Flag, Deprecated: "7.5"},
+	{Name: "User", Type: String},
+	{Name: "UserKnownHostsFile", Type: SpaceList},
+	{Name: "UseRoaming", Type: Flag, Since: "5.4", Deprecated: "7.2"},
+	{Name: "VerifyHostKeyDNS", Type: Flag},
+	{Name: "VisualHostKey", Type: Flag},
+	{Name: "XAuthLocation", Type: String},
+}
+
+var byNormalizedName = func() map[string]Keyword {
+	keywords := make(map[string]Keyword, len(catalog))
+	for _, k := range catalog {
+		keywords[normalized(k.Name)] = k
+	}
+	return keywords
+}()
+
+// normalized makes keywords comparable regardless of their casing and underscores, as ssh keywords are case insensitive
+// and ssh-aliases keywords may be written in snake case
+func normalized(keyword string) string {
+	return strings.ToLower(strings.ReplaceAll(keyword, "_", ""))
+}
+
+// Lookup finds a keyword in the catalog, keywords are matched regardless of their casing and underscores
+func Lookup(keyword string) (Keyword, bool) {
+	k, ok := byNormalizedName[normalized(keyword)]
+	return k, ok
+}
+
+// All returns all keywords of the catalog, ordered by their names
+func All() []Keyword {
+	all := make([]Keyword, len(catalog))
+	copy(all, catalog)
+	return all
+}
+
+// Suggest returns the catalog keyword that is the closest to the provided unknown keyword,
+// nothing is suggested when all keywords differ too much
+func Suggest(keyword string) (Keyword, bool) {
+	name := normalized(keyword)
+	limit := len(name)/3 + 1
+	var best Keyword
+	bestDistance := limit + 1
+	for _, k := range catalog {
+		distance := editDistance(name, normalized(k.Name))
+		if distance < bestDistance {
+			best, bestDistance = k, distance
+		}
+	}
+	return best, bestDistance <= limit
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,169 @@
+package keywords
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldLookupKeywordsRegardlessOfCasing(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []string{"IdentityFile", "identity_file", "identityfile", "IDENTITYFILE"}
+
+	for _, e := range entries {
+		// when
+		keyword, ok := Lookup(e)
+
+		// then
+		assert.True(t, ok, e)
+		assert.Equal(t, "IdentityFile", keyword.Name)
+		assert.Equal(t, String, keyword.Type)
+	}
+}
+
+func TestShouldDescribeSpaceSeparatedListsOfArguments(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []string{"CanonicalDomains", "GlobalKnownHostsFile", "UserKnownHostsFile", "IPQoS"}
+
+	for _, e := range entries {
+		// when
+		keyword, _ := Lookup(e)
+
+		// then
+		assert.Equal(t, SpaceList, keyword.Type, e)
+		assert.Equal(t, "space separated list", keyword.Type.String(), e)
+	}
+}
+
+func TestShouldNotFindUnknownKeywords(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, ok := Lookup("identiy_file")
+
+	// then
+	assert.False(t, ok)
+}
+
+func TestShouldRecordVersionsOfKeywords(t *testing.T) {
+	t.Parallel()
+
+	// when
+	proxyJump, _ := Lookup("ProxyJump")
+	pubkeyTypes, _ := Lookup("PubkeyAcceptedKeyTypes")
+
+	// then
+	assert.Equal(t, "7.3", proxyJump.Since)
+	assert.Equal(t, "", proxyJump.Deprecated)
+	assert.Equal(t, "8.5", pubkeyTypes.Deprecated)
+	assert.Equal(t, "PubkeyAcceptedAlgorithms", pubkeyTypes.ReplacedBy)
+}
+
+func TestShouldKnowAllKeywordsOfOpenSSHClient(t *testing.T) {
+	t.Parallel()
+
+	// given
+	// keywords documented by ssh_config(5) of OpenSSH 9.2, followed by client keywords of later versions
+	documented := strings.Fields(`
+		AddKeysToAgent AddressFamily BatchMode BindAddress BindInterface CanonicalDomains
+		CanonicalizeFallbackLocal CanonicalizeHostname CanonicalizeMaxDots CanonicalizePermittedCNAMEs
+		CASignatureAlgorithms CertificateFile CheckHostIP Ciphers ClearAllForwardings Compression
+		ConnectionAttempts ConnectTimeout ControlMaster ControlPath ControlPersist DynamicForward
+		EnableEscapeCommandline EnableSSHKeysign EscapeChar ExitOnForwardFailure FingerprintHash
+		ForkAfterAuthentication ForwardAgent ForwardX11 ForwardX11Timeout ForwardX11Trusted GatewayPorts
+		GlobalKnownHostsFile GSSAPIAuthentication GSSAPIDelegateCredentials HashKnownHosts
+		HostbasedAcceptedAlgorithms HostbasedAuthentication HostKeyAlgorithms HostKeyAlias Hostname
+		IdentitiesOnly IdentityAgent IdentityFile IgnoreUnknown Include IPQoS KbdInteractiveAuthentication
+		KbdInteractiveDevices KexAlgorithms KnownHostsCommand LocalCommand LocalForward LogLevel LogVerbose
+		MACs NoHostAuthenticationForLocalhost NumberOfPasswordPrompts PasswordAuthentication
+		PermitLocalCommand PermitRemoteOpen PKCS11Provider Port PreferredAuthentications ProxyCommand
+		ProxyJump ProxyUseFdpass PubkeyAcceptedAlgorithms PubkeyAuthentication RekeyLimit RemoteCommand
+		RemoteForward RequestTTY RequiredRSASize RevokedHostKeys SecurityKeyProvider SendEnv
+		ServerAliveCountMax ServerAliveInterval SessionType SetEnv StdinNull StreamLocalBindMask
+		StreamLocalBindUnlink StrictHostKeyChecking SyslogFacility TCPKeepAlive Tunnel TunnelDevice
+		UpdateHostKeys User UserKnownHostsFile VerifyHostKeyDNS VisualHostKey XAuthLocation
+		Tag ChannelTimeout ObscureKeystrokeTiming`)
+
+	for _, d := range documented {
+		// when
+		keyword, ok := Lookup(d)
+
+		// then
+		assert.True(t, ok, d)
+		assert.Empty(t, keyword.Deprecated, d)
+	}
+}
+
+func TestShouldSuggestNearestKeywords(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		input    string
+		expected string
+	}{
+		{"identiy_file", "IdentityFile"},
+		{"proxy_jmp", "ProxyJump"},
+		{"ServerAliveInterwal", "ServerAliveInterval"},
+		{"prot", "Port"},
+		{"forwardagnet", "ForwardAgent"},
+	}
+
+	for _, e := range entries {
+		// when
+		suggested, ok := Suggest(e.input)
+
+		// then
+		assert.True(t, ok, e.input)
+		assert.Equal(t, e.expected, suggested.Name, e.input)
+	}
+}
+
+func TestShouldNotSuggestDistantKeywords(t *testing.T) {
+	t.Parallel()
+
+	// when
+	_, ok := Suggest("use_keychain")
+
+	// then
+	assert.False(t, ok)
+}
+
+func TestCatalogShouldBeOrderedAndUnique(t *testing.T) {
+	t.Parallel()
+
+	// given
+	all := All()
+
+	// then
+	assert.True(t, sort.SliceIsSorted(all, func(i, j int) bool {
+		return strings.ToLower(all[i].Name) < strings.ToLower(all[j].Name)
+	}))
+	for _, k := range all {
+		if k.ReplacedBy != "" {
+			_, ok := Lookup(k.ReplacedBy)
+			assert.True(t, ok, k.Name)
+		}
+	}
+}
+
+func TestShouldParseStrictness(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []Strictness{Off, Warn, Error} {
+		// when
+		parsed, err := ParseStrictness(s.String())
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, s, parsed)
+	}
+	_, err := ParseStrictness("strict")
+	assert.EqualError(t, err, "invalid keyword strictness `strict`, expected `off`, `warn` or `error`")
+}
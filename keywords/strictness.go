@@ -0,0 +1,37 @@
+package keywords
+
+import (
+	"fmt"
+)
+
+// Strictness tells how keywords missing from the catalog are reported
+type Strictness int
+
+const (
+	// Off skips checking keywords
+	Off Strictness = iota
+	// Warn reports unknown and deprecated keywords as warnings
+	Warn
+	// Error reports unknown keywords as errors and deprecated keywords as warnings
+	Error
+)
+
+func (s Strictness) String() string {
+	switch s {
+	case Warn:
+		return "warn"
+	case Error:
+		return "error"
+	}
+	return "off"
+}
+
+// ParseStrictness converts one of `off`, `warn` or `error` into Strictness
+func ParseStrictness(value string) (Strictness, error) {
+	for _, s := range []Strictness{Off, Warn, Error} {
+		if s.String() == value {
+			return s, nil
+		}
+	}
+	return Off, fmt.Errorf("invalid keyword strictness `%s`, expected `off`, `warn` or `error`", value)
+}
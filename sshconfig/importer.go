@@ -0,0 +1,461 @@
+package sshconfig
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/config"
+)
+
+// Importer converts ssh_config sections into ssh-aliases HCL definitions.
+// Host sections with identical properties share named configs, that are extended by sections declaring
+// additional properties. Match sections become match definitions with embedded configs.
+type Importer struct {
+	hostnameRegexp *regexp.Regexp
+	numberRegexp   *regexp.Regexp
+}
+
+// NewImporter creates new instance of Importer
+func NewImporter() *Importer {
+	return &Importer{
+		hostnameRegexp: regexp.MustCompile(`^[a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?(\.[a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?)*$`),
+		numberRegexp:   regexp.MustCompile(`\d+`),
+	}
+}
+
+type importedProperty struct {
+	key   string
+	value interface{}
+}
+
+type importedHost struct {
+	block    Block
+	name     string
+	alias    string
+	hostname string
+	props    []importedProperty
+	config   string
+}
+
+type importedMatch struct {
+	block    Block
+	name     string
+	criteria []importedProperty
+	props    []importedProperty
+	top      bool
+	// moved tells that the section precedes Host sections, but it is placed after all hosts
+	moved bool
+	// skipped tells why the section cannot be converted into a match definition
+	skipped string
+}
+
+// matchCriteria are criteria of Match sections supported by match definitions, mapped to their number of arguments
+var matchCriteria = map[string]int{
+	"canonical":    0,
+	"all":          0,
+	"host":         1,
+	"originalhost": 1,
+	"user":         1,
+	"localuser":    1,
+	"exec":         1,
+}
+
+type sharedConfig struct {
+	name  string
+	props []importedProperty
+}
+
+// Import writes HCL definitions of provided sections
+func (i *Importer) Import(blocks []Block, writer io.Writer) error {
+	w := &hclWriter{writer: writer}
+	hosts := i.importedHosts(blocks)
+	matches := i.importedMatches(blocks)
+	shared := i.sharedConfigs(hosts)
+	for _, warning := range i.warnings(hosts, matches) {
+		w.printf("# warning: %s\n", warning)
+	}
+	for _, c := range shared {
+		w.printf("config %s {\n", hclString(c.name))
+		w.properties(c.props, "  ")
+		w.printf("}\n\n")
+	}
+	hints := i.rangeHints(hosts)
+	hostIdx, matchIdx := 0, 0
+	for _, b := range blocks {
+		if b.Kind != "Host" {
+			m := matches[matchIdx]
+			matchIdx++
+			if m.skipped != "" {
+				w.skipped(m)
+				continue
+			}
+			if m.moved {
+				w.printf("# warning: Match section (%s) precedes Host sections, but `%s` is placed after all hosts, "+
+					"so its values no longer take precedence over values of the hosts that follow it\n", m.block.Source, m.name)
+			}
+			w.match(m)
+			continue
+		}
+		h := hosts[hostIdx]
+		hostIdx++
+		for _, hint := range hints[h.name] {
+			w.printf("# hint: %s\n", hint)
+		}
+		w.host(h)
+	}
+	return w.err
+}
+
+func (i *Importer) importedHosts(blocks []Block) []*importedHost {
+	var hosts []*importedHost
+	names := map[string]int{}
+	for _, b := range blocks {
+		if b.Kind != "Host" {
+			continue
+		}
+		h := &importedHost{
+			block: b,
+			alias: strings.Join(b.Criteria, " "),
+		}
+		h.name = b.Criteria[0]
+		names[h.name]++
+		if names[h.name] > 1 {
+			h.name = fmt.Sprintf("%s-%d", h.name, names[h.name])
+		}
+		for _, p := range b.Properties {
+			if strings.EqualFold(p.Keyword, "HostName") && len(p.Values) == 1 && i.hostnameRegexp.MatchString(p.Values[0]) {
+				h.hostname = p.Values[0]
+				continue
+			}
+			h.props = append(h.props, importedProperty{config.Desanitize(p.Keyword), propertyValue(p.Values)})
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+// importedMatches converts Match sections, the ones preceding all Host sections are placed at the top
+// of the compiled file, the others follow all hosts, so the ones between Host sections are moved
+func (i *Importer) importedMatches(blocks []Block) []*importedMatch {
+	var matches []*importedMatch
+	hostSeen := false
+	for _, b := range blocks {
+		if b.Kind == "Host" {
+			hostSeen = true
+			for idx := len(matches) - 1; idx >= 0 && !matches[idx].top && !matches[idx].moved; idx-- {
+				matches[idx].moved = true
+			}
+			continue
+		}
+		m := &importedMatch{
+			block: b,
+			name:  fmt.Sprintf("match-%d", len(matches)+1),
+			top:   !hostSeen,
+		}
+		matches = append(matches, m)
+		m.criteria, m.skipped = importedCriteria(b.Criteria)
+		if m.skipped == "" && len(b.Properties) == 0 {
+			m.skipped = "it has no properties"
+		}
+		for _, p := range b.Properties {
+			m.props = append(m.props, importedProperty{config.Desanitize(p.Keyword), propertyValue(p.Values)})
+		}
+	}
+	return matches
+}
+
+// importedCriteria converts arguments of a Match section into attributes of a match definition,
+// or returns the reason why they cannot be converted
+func importedCriteria(args []string) ([]importedProperty, string) {
+	var criteria []importedProperty
+	declared := map[string]bool{}
+	for idx := 0; idx < len(args); idx++ {
+		keyword := strings.ToLower(args[idx])
+		argsCount, ok := matchCriteria[keyword]
+		switch {
+		case !ok:
+			return nil, fmt.Sprintf("`%s` criterion is not supported", args[idx])
+		case declared[keyword]:
+			return nil, fmt.Sprintf("`%s` criterion is repeated", args[idx])
+		case idx+argsCount >= len(args):
+			return nil, fmt.Sprintf("`%s` criterion has no argument", args[idx])
+		}
+		declared[keyword] = true
+		if argsCount == 0 {
+			criteria = append(criteria, importedProperty{keyword, true})
+			continue
+		}
+		idx++
+		var value interface{} = args[idx]
+		if patterns := strings.Split(args[idx], ","); keyword != "exec" && len(patterns) > 1 {
+			list := make([]interface{}, 0, len(patterns))
+			for _, p := range patterns {
+				list = append(list, p)
+			}
+			value = list
+		}
+		criteria = append(criteria, importedProperty{keyword, value})
+	}
+	return criteria, ""
+}
+
+func propertyValue(values []string) interface{} {
+	if len(values) > 1 {
+		list := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			list = append(list, v)
+		}
+		return list
+	}
+	if n, err := strconv.Atoi(values[0]); err == nil && strconv.Itoa(n) == values[0] {
+		return n
+	}
+	return values[0]
+}
+
+// sharedConfigs extracts configs of hosts with identical properties,
+// hosts with other properties extend the biggest shared config they include
+func (i *Importer) sharedConfigs(hosts []*importedHost) []sharedConfig {
+	groups := map[string][]*importedHost{}
+	var signatures []string
+	for _, h := range hosts {
+		if len(h.props) == 0 {
+			continue
+		}
+		s := signature(h.props)
+		if _, ok := groups[s]; !ok {
+			signatures = append(signatures, s)
+		}
+		groups[s] = append(groups[s], h)
+	}
+	var shared []sharedConfig
+	for _, s := range signatures {
+		if len(groups[s]) < 2 {
+			continue
+		}
+		c := sharedConfig{
+			name:  fmt.Sprintf("shared-%d", len(shared)+1),
+			props: groups[s][0].props,
+		}
+		shared = append(shared, c)
+		for _, h := range groups[s] {
+			h.config = c.name
+			h.props = nil
+		}
+	}
+	for _, h := range hosts {
+		if h.config != "" {
+			continue
+		}
+		var extended *sharedConfig
+		for idx, c := range shared {
+			if includesAll(h.props, c.props) && (extended == nil || len(c.props) > len(extended.props)) {
+				extended = &shared[idx]
+			}
+		}
+		if extended != nil {
+			h.config = extended.name
+			h.props = withoutAll(h.props, extended.props)
+		}
+	}
+	return shared
+}
+
+func signature(props []importedProperty) string {
+	entries := make([]string, 0, len(props))
+	for _, p := range props {
+		entries = append(entries, propertySignature(p))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
+func propertySignature(p importedProperty) string {
+	return fmt.Sprintf("%s=%#v", p.key, p.value)
+}
+
+func includesAll(props []importedProperty, included []importedProperty) bool {
+	return len(withoutAll(props, included)) == len(props)-len(included)
+}
+
+func withoutAll(props []importedProperty, removed []importedProperty) []importedProperty {
+	signatures := map[string]struct{}{}
+	for _, p := range removed {
+		signatures[propertySignature(p)] = struct{}{}
+	}
+	var remaining []importedProperty
+	for _, p := range props {
+		if _, ok := signatures[propertySignature(p)]; !ok {
+			remaining = append(remaining, p)
+		}
+	}
+	return remaining
+}
+
+func (i *Importer) warnings(hosts []*importedHost, matches []*importedMatch) []string {
+	var warnings []string
+	for _, h := range hosts {
+		warnings = append(warnings, placeholderWarnings(h.props, "host", h.name)...)
+	}
+	for _, m := range matches {
+		if m.skipped == "" {
+			warnings = append(warnings, placeholderWarnings(append(m.criteria, m.props...), "match", m.name)...)
+		}
+	}
+	return warnings
+}
+
+func placeholderWarnings(props []importedProperty, kind string, name string) []string {
+	var warnings []string
+	for _, p := range props {
+		if strings.Contains(fmt.Sprintf("%v", p.value), "${") {
+			warnings = append(warnings, fmt.Sprintf("`%s` of `%s` %s contains `${`, "+
+				"which is treated as a variable placeholder by ssh-aliases", p.key, name, kind))
+		}
+	}
+	return warnings
+}
+
+type rangeCandidate struct {
+	host  *importedHost
+	value int
+	width int
+}
+
+// rangeHints detects hosts that differ only by a number in alias and hostname
+// and suggests replacing them with a single host definition using a range expression
+func (i *Importer) rangeHints(hosts []*importedHost) map[string][]string {
+	groups := map[string][]rangeCandidate{}
+	var keys []string
+	for _, h := range hosts {
+		if h.hostname == "" || len(h.block.Criteria) != 1 || len(h.props) > 0 {
+			continue
+		}
+		numbers := i.numberRegexp.FindAllStringIndex(h.alias, -1)
+		if len(numbers) == 0 {
+			continue
+		}
+		last := numbers[len(numbers)-1]
+		number := h.alias[last[0]:last[1]]
+		if strings.Count(h.hostname, number) != 1 {
+			continue
+		}
+		value, _ := strconv.Atoi(number)
+		aliasTemplate := h.alias[:last[0]] + "{#1}" + h.alias[last[1]:]
+		hostnameTemplate := strings.Replace(h.hostname, number, "%s", 1)
+		key := strings.Join([]string{aliasTemplate, hostnameTemplate, h.config}, "\n")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], rangeCandidate{h, value, len(number)})
+	}
+	hints := map[string][]string{}
+	for _, key := range keys {
+		candidates := groups[key]
+		if len(candidates) < 2 {
+			continue
+		}
+		sort.Slice(candidates, func(a, b int) bool {
+			return candidates[a].value < candidates[b].value
+		})
+		first := candidates[0]
+		last := candidates[len(candidates)-1]
+		if last.value-first.value != len(candidates)-1 || first.width != len(strconv.Itoa(first.value)) {
+			continue
+		}
+		templates := strings.Split(key, "\n")
+		hostname := fmt.Sprintf(templates[1], fmt.Sprintf("[%d..%d]", first.value, last.value))
+		hint := fmt.Sprintf("hosts `%s` to `%s` could be defined with a single host definition: "+
+			"hostname = %s, alias = %s", first.host.name, last.host.name, hclString(hostname), hclString(templates[0]))
+		hints[candidates[0].host.name] = append(hints[candidates[0].host.name], hint)
+	}
+	return hints
+}
+
+type hclWriter struct {
+	writer io.Writer
+	err    error
+}
+
+func (w *hclWriter) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.writer, format, args...)
+}
+
+func (w *hclWriter) host(h *importedHost) {
+	w.printf("host %s {\n", hclString(h.name))
+	if h.hostname != "" {
+		w.printf("  hostname = %s\n", hclString(h.hostname))
+	}
+	w.printf("  alias = %s\n", hclString(h.alias))
+	switch {
+	case h.config != "" && len(h.props) == 0:
+		w.printf("  config = %s\n", hclString(h.config))
+	case h.config != "":
+		w.printf("  config = {\n")
+		w.printf("    _extend = %s\n", hclString(h.config))
+		w.properties(h.props, "    ")
+		w.printf("  }\n")
+	case len(h.props) > 0:
+		w.printf("  config = {\n")
+		w.properties(h.props, "    ")
+		w.printf("  }\n")
+	}
+	w.printf("}\n\n")
+}
+
+func (w *hclWriter) match(m *importedMatch) {
+	w.printf("match %s {\n", hclString(m.name))
+	for _, c := range m.criteria {
+		w.printf("  %s = %s\n", c.key, hclValue(c.value))
+	}
+	if m.top {
+		w.printf("  position = %s\n", hclString("top"))
+	}
+	w.printf("  config = {\n")
+	w.properties(m.props, "    ")
+	w.printf("  }\n")
+	w.printf("}\n\n")
+}
+
+func (w *hclWriter) skipped(m *importedMatch) {
+	b := m.block
+	w.printf("# %s section (%s) cannot be imported, %s, skipped:\n", b.Kind, b.Source, m.skipped)
+	w.printf("# %s %s\n", b.Kind, strings.Join(quotedArgs(b.Criteria), " "))
+	for _, p := range b.Properties {
+		for _, v := range p.Values {
+			w.printf("#     %s %s\n", p.Keyword, v)
+		}
+	}
+	w.printf("\n")
+}
+
+func (w *hclWriter) properties(props []importedProperty, indent string) {
+	for _, p := range props {
+		w.printf("%s%s = %s\n", indent, p.key, hclValue(p.value))
+	}
+}
+
+func hclValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return hclString(v)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			values = append(values, hclValue(e))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func hclString(str string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(str) + `"`
+}
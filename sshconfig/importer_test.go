@@ -0,0 +1,181 @@
+package sshconfig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldImportSSHConfig(t *testing.T) {
+	t.Parallel()
+
+	// given
+	blocks, err := NewParser("/home/user").ParseFile(filepath.Join(fixtureDir, "config"))
+	assert.NoError(t, err)
+	buffer := new(bytes.Buffer)
+
+	// when
+	err = NewImporter().Import(blocks, buffer)
+
+	// then
+	assert.NoError(t, err)
+	output, _ := os.ReadFile(filepath.Join(fixtureDir, "import_result"))
+	assert.Equal(t, string(output), buffer.String())
+}
+
+func TestShouldWarnAboutVariablePlaceholders(t *testing.T) {
+	t.Parallel()
+
+	// given
+	blocks := []Block{{
+		Kind:       "Host",
+		Criteria:   []string{"example"},
+		Properties: []Property{{"RemoteCommand", []string{"echo ${HOME}"}}},
+	}}
+	buffer := new(bytes.Buffer)
+
+	// when
+	err := NewImporter().Import(blocks, buffer)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "# warning: `remote_command` of `example` host contains `${`, "+
+		"which is treated as a variable placeholder by ssh-aliases\n"+
+		"host \"example\" {\n"+
+		"  alias = \"example\"\n"+
+		"  config = {\n"+
+		"    remote_command = \"echo ${HOME}\"\n"+
+		"  }\n"+
+		"}\n\n", buffer.String())
+}
+
+func TestShouldImportMatchSections(t *testing.T) {
+	t.Parallel()
+
+	// given
+	blocks := []Block{{
+		Kind:       "Match",
+		Criteria:   []string{"canonical", "Host", "*.prod.example.com,prod-*", "user", "deploy"},
+		Properties: []Property{{"IdentityFile", []string{"~/.ssh/deploy.pem"}}},
+	}, {
+		Kind:     "Host",
+		Criteria: []string{"web"},
+	}, {
+		Kind:       "Match",
+		Criteria:   []string{"all"},
+		Properties: []Property{{"ServerAliveInterval", []string{"30"}}},
+	}}
+	buffer := new(bytes.Buffer)
+
+	// when
+	err := NewImporter().Import(blocks, buffer)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "match \"match-1\" {\n"+
+		"  canonical = true\n"+
+		"  host = [\"*.prod.example.com\", \"prod-*\"]\n"+
+		"  user = \"deploy\"\n"+
+		"  position = \"top\"\n"+
+		"  config = {\n"+
+		"    identity_file = \"~/.ssh/deploy.pem\"\n"+
+		"  }\n"+
+		"}\n\n"+
+		"host \"web\" {\n"+
+		"  alias = \"web\"\n"+
+		"}\n\n"+
+		"match \"match-2\" {\n"+
+		"  all = true\n"+
+		"  config = {\n"+
+		"    server_alive_interval = 30\n"+
+		"  }\n"+
+		"}\n\n", buffer.String())
+}
+
+func TestShouldWarnAboutMatchSectionsBetweenHosts(t *testing.T) {
+	t.Parallel()
+
+	// given
+	blocks := []Block{{
+		Kind:     "Host",
+		Criteria: []string{"web"},
+	}, {
+		Kind:       "Match",
+		Criteria:   []string{"user", "deploy"},
+		Properties: []Property{{"Port", []string{"2222"}}},
+		Source:     "config:4",
+	}, {
+		Kind:     "Host",
+		Criteria: []string{"db"},
+	}, {
+		Kind:       "Match",
+		Criteria:   []string{"all"},
+		Properties: []Property{{"Port", []string{"22"}}},
+		Source:     "config:10",
+	}}
+	buffer := new(bytes.Buffer)
+
+	// when
+	err := NewImporter().Import(blocks, buffer)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "host \"web\" {\n"+
+		"  alias = \"web\"\n"+
+		"}\n\n"+
+		"# warning: Match section (config:4) precedes Host sections, but `match-1` is placed after all hosts, "+
+		"so its values no longer take precedence over values of the hosts that follow it\n"+
+		"match \"match-1\" {\n"+
+		"  user = \"deploy\"\n"+
+		"  config = {\n"+
+		"    port = 2222\n"+
+		"  }\n"+
+		"}\n\n"+
+		"host \"db\" {\n"+
+		"  alias = \"db\"\n"+
+		"}\n\n"+
+		"match \"match-2\" {\n"+
+		"  all = true\n"+
+		"  config = {\n"+
+		"    port = 22\n"+
+		"  }\n"+
+		"}\n\n", buffer.String())
+}
+
+func TestShouldSkipMatchSectionsWithUnsupportedCriteria(t *testing.T) {
+	t.Parallel()
+
+	// given
+	entries := []struct {
+		criteria []string
+		expected string
+	}{
+		{[]string{"!host", "*.example.com"}, "`!host` criterion is not supported"},
+		{[]string{"final", "host", "*.example.com"}, "`final` criterion is not supported"},
+		{[]string{"host", "a", "host", "b"}, "`host` criterion is repeated"},
+		{[]string{"user"}, "`user` criterion has no argument"},
+	}
+
+	for _, e := range entries {
+		blocks := []Block{{
+			Kind:       "Match",
+			Criteria:   e.criteria,
+			Properties: []Property{{"User", []string{"me"}}},
+			Source:     "config:1",
+		}}
+		buffer := new(bytes.Buffer)
+
+		// when
+		err := NewImporter().Import(blocks, buffer)
+
+		// then
+		assert.NoError(t, err)
+		assert.Equal(t, "# Match section (config:1) cannot be imported, "+e.expected+", skipped:\n"+
+			"# Match "+strings.Join(e.criteria, " ")+"\n"+
+			"#     User me\n\n", buffer.String())
+	}
+}
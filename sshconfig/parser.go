@@ -0,0 +1,257 @@
+// Package sshconfig provides parsing of OpenSSH client config files and their conversion into ssh-aliases configs
+package sshconfig
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dankraw/ssh-aliases/keywords"
+)
+
+// Block is a single Host or Match section of ssh_config
+type Block struct {
+	// Kind is either `Host` or `Match`
+	Kind string
+	// Criteria contains host patterns for Host sections or criteria arguments for Match sections
+	Criteria   []string
+	Properties []Property
+	// Source points the file and line where the section begins
+	Source string
+}
+
+// Property is a keyword with all values declared for it in a single section, in order of declaration
+type Property struct {
+	Keyword string
+	Values  []string
+}
+
+// multiValuedKeywords can be declared multiple times in a section, all declared values are used by ssh
+var multiValuedKeywords = map[string]struct{}{
+	"certificatefile": {},
+	"dynamicforward":  {},
+	"identityfile":    {},
+	"localforward":    {},
+	"remoteforward":   {},
+	"sendenv":         {},
+	"setenv":          {},
+}
+
+// commandKeywords take the rest of the line as their value
+var commandKeywords = map[string]struct{}{
+	"knownhostscommand": {},
+	"localcommand":      {},
+	"proxycommand":      {},
+	"remotecommand":     {},
+}
+
+const maxIncludeDepth = 16
+
+// Parser reads ssh_config files
+type Parser struct {
+	homeDir string
+}
+
+// NewParser creates new instance of Parser, `~` in included paths is resolved to provided home dir
+func NewParser(homeDir string) *Parser {
+	return &Parser{
+		homeDir: homeDir,
+	}
+}
+
+type parsingState struct {
+	baseDir string
+	blocks  []Block
+	current *Block
+}
+
+// ParseFile reads ssh_config file and all files included by it, relative includes are resolved against the file's directory.
+// Properties declared before the first section are returned as a `Host *` section.
+func (p *Parser) ParseFile(path string) ([]Block, error) {
+	state := &parsingState{
+		baseDir: filepath.Dir(path),
+	}
+	if err := p.parseFile(path, state, 0); err != nil {
+		return nil, err
+	}
+	if state.current != nil {
+		state.blocks = append(state.blocks, *state.current)
+	}
+	return state.blocks, nil
+}
+
+func (p *Parser) parseFile(path string, state *parsingState, depth int) error {
+	if depth > maxIncludeDepth {
+		return fmt.Errorf("error in `%s`: too many nested includes", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read ssh config file: %s", err.Error())
+	}
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for lines.Scan() {
+		lineNo++
+		keyword, rest, args, err := splitLine(lines.Text())
+		if err != nil {
+			return fmt.Errorf("error in `%s` at line %d: %s", path, lineNo, err.Error())
+		}
+		if keyword == "" {
+			continue
+		}
+		source := fmt.Sprintf("%s:%d", path, lineNo)
+		switch strings.ToLower(keyword) {
+		case "host", "match":
+			if len(args) == 0 {
+				return fmt.Errorf("error in `%s`: %s requires at least one argument", source, keyword)
+			}
+			if state.current != nil {
+				state.blocks = append(state.blocks, *state.current)
+			}
+			kind := "Host"
+			if strings.ToLower(keyword) == "match" {
+				kind = "Match"
+			}
+			state.current = &Block{Kind: kind, Criteria: args, Source: source}
+		case "include":
+			for _, pattern := range args {
+				if err := p.parseIncluded(pattern, state, depth); err != nil {
+					return err
+				}
+			}
+		default:
+			if len(args) == 0 {
+				return fmt.Errorf("error in `%s`: missing value of `%s`", source, keyword)
+			}
+			if state.current == nil {
+				state.current = &Block{Kind: "Host", Criteria: []string{"*"}, Source: source}
+			}
+			state.current.add(keyword, propertyValues(keyword, rest, args)...)
+		}
+	}
+	return lines.Err()
+}
+
+func (p *Parser) parseIncluded(pattern string, state *parsingState, depth int) error {
+	if strings.HasPrefix(pattern, "~/") {
+		pattern = filepath.Join(p.homeDir, pattern[2:])
+	} else if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(state.baseDir, pattern)
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid include pattern `%s`: %s", pattern, err.Error())
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		if err = p.parseFile(f, state, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// propertyValues converts arguments of a keyword into values of a property. A single argument is kept unquoted,
+// keywords taking several arguments get a value for each of them, quoted as in ssh config.
+// Commands, forward specifications and values of unknown keywords are kept as a single value.
+func propertyValues(keyword string, rest string, args []string) []string {
+	if _, ok := commandKeywords[strings.ToLower(keyword)]; ok {
+		return []string{rest}
+	}
+	known, ok := keywords.Lookup(keyword)
+	switch {
+	case ok && (known.Type == keywords.SpaceList || known.Type == keywords.Environment):
+		return quotedArgs(args)
+	case ok && known.Type != keywords.Forward && len(args) == 1:
+		return args
+	}
+	return []string{strings.Join(quotedArgs(args), " ")}
+}
+
+func (b *Block) add(keyword string, values ...string) {
+	lower := strings.ToLower(keyword)
+	for i, prop := range b.Properties {
+		if strings.ToLower(prop.Keyword) != lower {
+			continue
+		}
+		// for single-valued keywords ssh uses the first declared value
+		if _, ok := multiValuedKeywords[lower]; ok {
+			b.Properties[i].Values = append(prop.Values, values...)
+		}
+		return
+	}
+	b.Properties = append(b.Properties, Property{Keyword: keyword, Values: values})
+}
+
+// splitLine returns the keyword, the rest of the line and arguments parsed from it,
+// keyword and arguments may be separated with whitespace or `=`
+func splitLine(line string) (string, string, []string, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", nil, nil
+	}
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return line, "", nil, nil
+	}
+	keyword := line[:end]
+	rest := strings.TrimLeft(line[end:], " \t")
+	if strings.HasPrefix(rest, "=") {
+		rest = strings.TrimLeft(rest[1:], " \t")
+	}
+	args, err := splitArgs(rest)
+	return keyword, rest, args, err
+}
+
+func splitArgs(str string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	quoted := false
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(str) && (str[i+1] == '"' || str[i+1] == '\\'):
+			i++
+			arg.WriteByte(str[i])
+		case c == '"':
+			quoted = !quoted
+			inArg = true
+		case !quoted && (c == ' ' || c == '\t'):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case !quoted && !inArg && c == '#':
+			// the rest of the line is a comment
+			return args, nil
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quoted string")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// quotedArgs restores quotes of arguments containing whitespace or `#`, so arguments can be split again by ssh
+func quotedArgs(args []string) []string {
+	quoted := make([]string, 0, len(args))
+	for _, a := range args {
+		if strings.ContainsAny(a, " \t#") || a == "" {
+			a = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(a) + `"`
+		}
+		quoted = append(quoted, a)
+	}
+	return quoted
+}
@@ -0,0 +1,81 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fixtureDir = "test-fixtures"
+
+func TestShouldParseSSHConfigWithIncludedFiles(t *testing.T) {
+	t.Parallel()
+
+	// when
+	blocks, err := NewParser("/home/user").ParseFile(filepath.Join(fixtureDir, "config"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 9)
+	assert.Equal(t, Block{
+		Kind:       "Host",
+		Criteria:   []string{"*"},
+		Properties: []Property{{"Compression", []string{"yes"}}},
+		Source:     "test-fixtures/config:2",
+	}, blocks[0])
+	assert.Equal(t, Block{
+		Kind:     "Host",
+		Criteria: []string{"db1"},
+		Properties: []Property{
+			{"HostName", []string{"db1.example.com"}},
+			{"LocalForward", []string{"5432 localhost:5432", "5433 localhost:5433"}},
+		},
+		Source: "test-fixtures/conf.d/db.conf:1",
+	}, blocks[1])
+	assert.Equal(t, Block{
+		Kind:     "Host",
+		Criteria: []string{"bastion"},
+		Properties: []Property{
+			{"HostName", []string{"10.0.0.1"}},
+			{"User", []string{"admin"}},
+			{"ProxyCommand", []string{`ssh -W %h:%p "gateway"`}},
+		},
+		Source: "test-fixtures/config:23",
+	}, blocks[7])
+	assert.Equal(t, Block{
+		Kind:       "Match",
+		Criteria:   []string{"host", "*.internal", "exec", "test -f ~/.vpn"},
+		Properties: []Property{{"ForwardAgent", []string{"yes"}}},
+		Source:     "test-fixtures/config:29",
+	}, blocks[8])
+}
+
+func TestShouldFailOnUnterminatedQuotes(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "config")
+	_ = os.WriteFile(file, []byte("Host example\n  User \"admin\n"), 0o600)
+
+	// when
+	_, err := NewParser("/home/user").ParseFile(file)
+
+	// then
+	assert.EqualError(t, err, "error in `"+file+"` at line 2: unterminated quoted string")
+}
+
+func TestShouldFailOnRecursiveIncludes(t *testing.T) {
+	t.Parallel()
+
+	// given
+	file := filepath.Join(t.TempDir(), "config")
+	_ = os.WriteFile(file, []byte("Include config\n"), 0o600)
+
+	// when
+	_, err := NewParser("/home/user").ParseFile(file)
+
+	// then
+	assert.EqualError(t, err, "error in `"+file+"`: too many nested includes")
+}